@@ -0,0 +1,45 @@
+package chain
+
+import "fmt"
+
+type batcher[T1, T2 any] struct {
+	size int
+	m    Runnable[[][]T1, [][]T2]
+}
+
+func (b batcher[T1, T2]) Invoke(in []T1) (out []T2, err error) {
+	if in == nil {
+		return nil, nil
+	}
+	batches := make([][]T1, 0, (len(in)+b.size-1)/b.size)
+	for i := 0; i < len(in); i += b.size {
+		batches = append(batches, in[i:min(i+b.size, len(in)):min(i+b.size, len(in))])
+	}
+	results, err := b.m.Invoke(batches)
+	if err != nil {
+		return nil, err
+	}
+	out = make([]T2, 0, len(in))
+	for i, result := range results {
+		if len(result) != len(batches[i]) {
+			return nil, fmt.Errorf("batch %d: expected %d results, got %d", i, len(batches[i]), len(result))
+		}
+		out = append(out, result...)
+	}
+	return out, nil
+}
+
+// Batch takes a Runnable instance that processes a batch of elements and returns a new Runnable instance
+// that splits its input slice into batches of at most size elements, invokes r for each batch with at most
+// workers batches in flight, and reassembles the results in order.
+//
+// The Runnable instance r must return exactly one result per element of its input batch.
+func Batch[T1, T2 any](r Runnable[[]T1, []T2], size, workers int) Runnable[[]T1, []T2] {
+	if size <= 0 {
+		panic("non-positive size for Batch")
+	}
+	return batcher[T1, T2]{
+		size: size,
+		m:    Map(r, WithConcurrency(workers)),
+	}
+}
@@ -0,0 +1,51 @@
+package chain_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestBatch(t *testing.T) {
+	var calls int32
+	r := chain.Batch(chain.Func(func(batch []int) []int {
+		atomic.AddInt32(&calls, 1)
+		if len(batch) > 3 {
+			panic("batch too large")
+		}
+		out := make([]int, len(batch))
+		for i, v := range batch {
+			out[i] = v * 2
+		}
+		return out
+	}), 3, 2)
+	in := make([]int, 10)
+	for i := range in {
+		in[i] = i
+	}
+	out, err := r.Invoke(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("expected: %d results, got: %d", len(in), len(out))
+	}
+	for i, v := range out {
+		if v != i*2 {
+			t.Fatalf("expected: %d, got: %d", i*2, v)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 4 {
+		t.Fatalf("expected: 4 batches, got: %d", n)
+	}
+}
+
+func TestBatch_ResultMismatch(t *testing.T) {
+	r := chain.Batch(chain.Func(func(batch []int) []int {
+		return batch[:1]
+	}), 2, 1)
+	if _, err := r.Invoke([]int{1, 2, 3}); err == nil {
+		t.Fatal("expected: error for mismatched result count")
+	}
+}
@@ -0,0 +1,73 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+const benchDepth = 16
+
+func inc(i int) int {
+	return i + 1
+}
+
+// opaque hides the stages of a Runnable instance, so chains built from it are nested instead of flattened.
+type opaque[T1, T2 any] struct {
+	r chain.Runnable[T1, T2]
+}
+
+func (o opaque[T1, T2]) Invoke(in T1) (T2, error) {
+	return o.r.Invoke(in)
+}
+
+func BenchmarkChain2_Nested(b *testing.B) {
+	var r chain.Runnable[int, int] = chain.Func(inc)
+	for i := 0; i < benchDepth; i++ {
+		r = chain.Chain2(opaque[int, int]{r}, chain.Func(inc))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Invoke(1000)
+	}
+}
+
+func BenchmarkChain2_Flattened(b *testing.B) {
+	var r chain.Runnable[int, int] = chain.Func(inc)
+	for i := 0; i < benchDepth; i++ {
+		r = chain.Chain2(r, chain.Func(inc))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Invoke(1000)
+	}
+}
+
+func BenchmarkPipe(b *testing.B) {
+	p := chain.New(chain.Func(inc))
+	for i := 0; i < benchDepth; i++ {
+		p = p.Then(chain.Func(inc))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Invoke(1000)
+	}
+}
+
+func BenchmarkPipe_Middleware(b *testing.B) {
+	p := chain.New(chain.Func(inc))
+	for i := 0; i < benchDepth; i++ {
+		p = p.Then(chain.Func(inc))
+	}
+	r := chain.Wrap(p.Build(), func(stage int, in any, next chain.Invoker) (any, error) {
+		return next(in)
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Invoke(1000)
+	}
+}
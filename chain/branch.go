@@ -0,0 +1,102 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoMatch is the default error returned by Switch when no case matches the input and no default is set.
+var ErrNoMatch = errors.New("no matching case")
+
+type branch[T1, T2 any] struct {
+	pred func(T1) bool
+	then Runnable[T1, T2]
+	els  Runnable[T1, T2]
+}
+
+func (b branch[T1, T2]) Invoke(in T1) (out T2, err error) {
+	if b.pred(in) {
+		return b.then.Invoke(in)
+	}
+	return b.els.Invoke(in)
+}
+
+// If takes a predicate and two Runnable instances and returns a new Runnable instance that invokes
+// then if the predicate is satisfied by the input, or els otherwise.
+func If[F ~func(T1) bool, T1, T2 any](pred F, then, els Runnable[T1, T2]) Runnable[T1, T2] {
+	if pred == nil {
+		panic("nil predicate for If")
+	}
+	return branch[T1, T2]{
+		pred: pred,
+		then: then,
+		els:  els,
+	}
+}
+
+type switchOptions[K comparable] struct {
+	noMatch func(K) error
+}
+
+// SwitchOption is a configuration option for the Switch function.
+type SwitchOption[K comparable] func(*switchOptions[K])
+
+// WithNoMatch sets the function that creates the error returned when no case matches the key.
+func WithNoMatch[K comparable](f func(K) error) SwitchOption[K] {
+	if f == nil {
+		panic("nil function for WithNoMatch")
+	}
+	return func(o *switchOptions[K]) {
+		o.noMatch = f
+	}
+}
+
+func (o *switchOptions[K]) apply(opts []SwitchOption[K]) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+type switcher[K comparable, T1, T2 any] struct {
+	key     func(T1) K
+	cases   map[K]Runnable[T1, T2]
+	def     Runnable[T1, T2]
+	noMatch func(K) error
+}
+
+func (s switcher[K, T1, T2]) Invoke(in T1) (out T2, err error) {
+	k := s.key(in)
+	if r, ok := s.cases[k]; ok {
+		return r.Invoke(in)
+	}
+	if s.def != nil {
+		return s.def.Invoke(in)
+	}
+	if s.noMatch != nil {
+		return out, s.noMatch(k)
+	}
+	return out, fmt.Errorf("%w: %v", ErrNoMatch, k)
+}
+
+// Switch takes a key function, a map of cases and a default Runnable instance and returns a new Runnable instance
+// that invokes the case matching the key of the input. If no case matches, def is invoked; if def is nil,
+// an error wrapping ErrNoMatch (or the error created by WithNoMatch) is returned.
+//
+// The cases map is copied, so later changes to it do not affect the returned Runnable.
+func Switch[K comparable, T1, T2 any](key func(T1) K, cases map[K]Runnable[T1, T2], def Runnable[T1, T2], options ...SwitchOption[K]) Runnable[T1, T2] {
+	if key == nil {
+		panic("nil key function for Switch")
+	}
+	var o switchOptions[K]
+	o.apply(options)
+	m := make(map[K]Runnable[T1, T2], len(cases))
+	for k, r := range cases {
+		m[k] = r
+	}
+	return switcher[K, T1, T2]{
+		key:     key,
+		cases:   m,
+		def:     def,
+		noMatch: o.noMatch,
+	}
+}
@@ -0,0 +1,83 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestIf(t *testing.T) {
+	r := chain.If(func(i int) bool {
+		return i >= 0
+	}, chain.Func(strconv.Itoa), chain.Func(func(i int) string {
+		return "negative"
+	}))
+	for _, tt := range []struct {
+		in   int
+		want string
+	}{
+		{1, "1"},
+		{-1, "negative"},
+	} {
+		out, err := r.Invoke(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Fatalf("expected: %s, got: %s", tt.want, out)
+		}
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	parity := func(i int) int {
+		return i & 1
+	}
+	r := chain.Switch(parity, map[int]chain.Runnable[int, string]{
+		0: chain.Func(func(i int) string { return "even" }),
+		1: chain.Func(func(i int) string { return "odd" }),
+	}, nil)
+	for _, tt := range []struct {
+		in   int
+		want string
+	}{
+		{2, "even"},
+		{3, "odd"},
+	} {
+		out, err := r.Invoke(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Fatalf("expected: %s, got: %s", tt.want, out)
+		}
+	}
+}
+
+func TestSwitch_NoMatch(t *testing.T) {
+	id := func(s string) string {
+		return s
+	}
+	cases := map[string]chain.Runnable[string, int]{
+		"a": chain.Func(func(s string) int { return 1 }),
+	}
+	r := chain.Switch(id, cases, nil)
+	if _, err := r.Invoke("b"); !errors.Is(err, chain.ErrNoMatch) {
+		t.Fatalf("expected: %v, got: %v", chain.ErrNoMatch, err)
+	}
+
+	errCustom := errors.New("custom")
+	r = chain.Switch(id, cases, nil, chain.WithNoMatch(func(string) error {
+		return errCustom
+	}))
+	if _, err := r.Invoke("b"); !errors.Is(err, errCustom) {
+		t.Fatalf("expected: %v, got: %v", errCustom, err)
+	}
+
+	r = chain.Switch(id, cases, chain.Func(func(s string) int { return 0 }))
+	if out, err := r.Invoke("b"); err != nil || out != 0 {
+		t.Fatalf("expected: 0, got: %d, %v", out, err)
+	}
+}
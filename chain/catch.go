@@ -0,0 +1,23 @@
+package chain
+
+type catcher[T1, T2 any] struct {
+	r       Runnable[T1, T2]
+	handler func(T1, error) (T2, error)
+}
+
+func (c catcher[T1, T2]) Invoke(in T1) (out T2, err error) {
+	if out, err = c.r.Invoke(in); err != nil {
+		return c.handler(in, err)
+	}
+	return
+}
+
+// Catch takes a Runnable instance and an error handler and returns a new Runnable instance that calls the handler
+// with the input and the error if the invocation fails. The handler may return a substitute output and a nil error
+// to recover, or a non-nil error to abort the chain.
+func Catch[T1, T2 any](r Runnable[T1, T2], handler func(T1, error) (T2, error)) Runnable[T1, T2] {
+	if handler == nil {
+		panic("nil handler for Catch")
+	}
+	return catcher[T1, T2]{r: r, handler: handler}
+}
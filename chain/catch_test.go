@@ -0,0 +1,35 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestCatch(t *testing.T) {
+	r := chain.Chain2(chain.Catch(chain.Func2(strconv.Atoi), func(s string, err error) (int, error) {
+		return -1, nil
+	}), chain.Func(strconv.Itoa))
+	out, err := r.Invoke("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "-1" {
+		t.Fatalf("expected: -1, got: %s", out)
+	}
+}
+
+func TestCatch_MapError(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	r := chain.Catch(chain.Func2(strconv.Atoi), func(s string, err error) (int, error) {
+		return 0, errInvalid
+	})
+	if _, err := r.Invoke("abc"); !errors.Is(err, errInvalid) {
+		t.Fatalf("expected: %v, got: %v", errInvalid, err)
+	}
+	if out, err := r.Invoke("42"); err != nil || out != 42 {
+		t.Fatalf("expected: 42, got: %d, %v", out, err)
+	}
+}
@@ -29,6 +29,48 @@ func Func2[F ~func(T1) (T2, error), T1, T2 any](f F) Runnable[T1, T2] {
 	return fn2[T1, T2](f)
 }
 
+// ToFunc takes a Runnable instance and returns a function that invokes it.
+func ToFunc[T1, T2 any](r Runnable[T1, T2]) func(T1) (T2, error) {
+	return r.Invoke
+}
+
+// MustFunc takes a Runnable instance and returns a function that invokes it and panics if the invocation fails.
+func MustFunc[T1, T2 any](r Runnable[T1, T2]) func(T1) T2 {
+	return func(in T1) T2 {
+		out, err := r.Invoke(in)
+		if err != nil {
+			panic(err)
+		}
+		return out
+	}
+}
+
+// identity is a type that returns its input unchanged.
+type identity[T any] struct{}
+
+func (identity[T]) Invoke(in T) (out T, err error) {
+	return in, nil
+}
+
+// Identity returns a Runnable instance that returns its input unchanged.
+func Identity[T any]() Runnable[T, T] {
+	return identity[T]{}
+}
+
+// constant is a type that ignores its input and returns a constant value.
+type constant[T1, T2 any] struct {
+	value T2
+}
+
+func (c constant[T1, T2]) Invoke(in T1) (out T2, err error) {
+	return c.value, nil
+}
+
+// Const returns a Runnable instance that ignores its input and returns the value v.
+func Const[T1, T2 any](v T2) Runnable[T1, T2] {
+	return constant[T1, T2]{value: v}
+}
+
 type chain2[T1, T2, T3 any] struct {
 	r1 Runnable[T1, T2]
 	r2 Runnable[T2, T3]
@@ -37,13 +79,36 @@ type chain2[T1, T2, T3 any] struct {
 func (c chain2[T1, T2, T3]) Invoke(in T1) (out T3, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
-	return c.r2.Invoke(x)
+	if out, err = c.r2.Invoke(x); err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+	}
+	return
+}
+
+func (c chain2[T1, T2, T3]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain2[T1, T2, T3]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain2[T1, T2, T3]) String() string {
+	return format(c.flatten())
 }
 
 // Chain2 takes 2 Runnable instances and returns a new Runnable instance that chains the two together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain2[R1 Runnable[T1, T2], R2 Runnable[T2, T3], T1, T2, T3 any](r1 R1, r2 R2) Runnable[T1, T3] {
+	if composed(r1, r2) {
+		return newPipe[T1, T3](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2)))
+	}
 	return chain2[T1, T2, T3]{
 		r1: r1,
 		r2: r2,
@@ -59,17 +124,41 @@ type chain3[T1, T2, T3, T4 any] struct {
 func (c chain3[T1, T2, T3, T4]) Invoke(in T1) (out T4, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
-	return c.r3.Invoke(y)
+	if out, err = c.r3.Invoke(y); err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+	}
+	return
+}
+
+func (c chain3[T1, T2, T3, T4]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain3[T1, T2, T3, T4]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain3[T1, T2, T3, T4]) String() string {
+	return format(c.flatten())
 }
 
 // Chain3 takes 3 Runnable instances and returns a new Runnable instance that chains the three together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain3[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], T1, T2, T3, T4 any](r1 R1, r2 R2, r3 R3) Runnable[T1, T4] {
+	if composed(r1, r2, r3) {
+		return newPipe[T1, T4](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3)))
+	}
 	return chain3[T1, T2, T3, T4]{
 		r1: r1,
 		r2: r2,
@@ -87,21 +176,46 @@ type chain4[T1, T2, T3, T4, T5 any] struct {
 func (c chain4[T1, T2, T3, T4, T5]) Invoke(in T1) (out T5, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
-	return c.r4.Invoke(z)
+	if out, err = c.r4.Invoke(z); err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+	}
+	return
+}
+
+func (c chain4[T1, T2, T3, T4, T5]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain4[T1, T2, T3, T4, T5]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain4[T1, T2, T3, T4, T5]) String() string {
+	return format(c.flatten())
 }
 
 // Chain4 takes 4 Runnable instances and returns a new Runnable instance that chains the four together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain4[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], T1, T2, T3, T4, T5 any](r1 R1, r2 R2, r3 R3, r4 R4) Runnable[T1, T5] {
+	if composed(r1, r2, r3, r4) {
+		return newPipe[T1, T5](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4)))
+	}
 	return chain4[T1, T2, T3, T4, T5]{
 		r1: r1,
 		r2: r2,
@@ -121,25 +235,51 @@ type chain5[T1, T2, T3, T4, T5, T6 any] struct {
 func (c chain5[T1, T2, T3, T4, T5, T6]) Invoke(in T1) (out T6, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
-	return c.r5.Invoke(w)
+	if out, err = c.r5.Invoke(w); err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+	}
+	return
+}
+
+func (c chain5[T1, T2, T3, T4, T5, T6]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain5[T1, T2, T3, T4, T5, T6]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain5[T1, T2, T3, T4, T5, T6]) String() string {
+	return format(c.flatten())
 }
 
 // Chain5 takes 5 Runnable instances and returns a new Runnable instance that chains the five together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain5[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], T1, T2, T3, T4, T5, T6 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5) Runnable[T1, T6] {
+	if composed(r1, r2, r3, r4, r5) {
+		return newPipe[T1, T6](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5)))
+	}
 	return chain5[T1, T2, T3, T4, T5, T6]{
 		r1: r1,
 		r2: r2,
@@ -161,29 +301,56 @@ type chain6[T1, T2, T3, T4, T5, T6, T7 any] struct {
 func (c chain6[T1, T2, T3, T4, T5, T6, T7]) Invoke(in T1) (out T7, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
 	u, err := c.r5.Invoke(w)
 	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
 		return
 	}
-	return c.r6.Invoke(u)
+	if out, err = c.r6.Invoke(u); err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+	}
+	return
+}
+
+func (c chain6[T1, T2, T3, T4, T5, T6, T7]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5), erase(c.r6)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain6[T1, T2, T3, T4, T5, T6, T7]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain6[T1, T2, T3, T4, T5, T6, T7]) String() string {
+	return format(c.flatten())
 }
 
 // Chain6 takes 6 Runnable instances and returns a new Runnable instance that chains the six together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain6[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], R6 Runnable[T6, T7], T1, T2, T3, T4, T5, T6, T7 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6) Runnable[T1, T7] {
+	if composed(r1, r2, r3, r4, r5, r6) {
+		return newPipe[T1, T7](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5), stagesOf[T6, T7](r6)))
+	}
 	return chain6[T1, T2, T3, T4, T5, T6, T7]{
 		r1: r1,
 		r2: r2,
@@ -207,33 +374,61 @@ type chain7[T1, T2, T3, T4, T5, T6, T7, T8 any] struct {
 func (c chain7[T1, T2, T3, T4, T5, T6, T7, T8]) Invoke(in T1) (out T8, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
 	u, err := c.r5.Invoke(w)
 	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
 		return
 	}
 	v, err := c.r6.Invoke(u)
 	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
 		return
 	}
-	return c.r7.Invoke(v)
+	if out, err = c.r7.Invoke(v); err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
+	}
+	return
+}
+
+func (c chain7[T1, T2, T3, T4, T5, T6, T7, T8]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5), erase(c.r6), erase(c.r7)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain7[T1, T2, T3, T4, T5, T6, T7, T8]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain7[T1, T2, T3, T4, T5, T6, T7, T8]) String() string {
+	return format(c.flatten())
 }
 
 // Chain7 takes 7 Runnable instances and returns a new Runnable instance that chains the seven together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain7[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], R6 Runnable[T6, T7], R7 Runnable[T7, T8], T1, T2, T3, T4, T5, T6, T7, T8 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7) Runnable[T1, T8] {
+	if composed(r1, r2, r3, r4, r5, r6, r7) {
+		return newPipe[T1, T8](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5), stagesOf[T6, T7](r6), stagesOf[T7, T8](r7)))
+	}
 	return chain7[T1, T2, T3, T4, T5, T6, T7, T8]{
 		r1: r1,
 		r2: r2,
@@ -259,37 +454,66 @@ type chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9 any] struct {
 func (c chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) Invoke(in T1) (out T9, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
 	u, err := c.r5.Invoke(w)
 	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
 		return
 	}
 	v, err := c.r6.Invoke(u)
 	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
 		return
 	}
 	t, err := c.r7.Invoke(v)
 	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
 		return
 	}
-	return c.r8.Invoke(t)
+	if out, err = c.r8.Invoke(t); err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
+	}
+	return
+}
+
+func (c chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5), erase(c.r6), erase(c.r7), erase(c.r8)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) String() string {
+	return format(c.flatten())
 }
 
 // Chain8 takes 8 Runnable instances and returns a new Runnable instance that chains the eight together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain8[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], R6 Runnable[T6, T7], R7 Runnable[T7, T8], R8 Runnable[T8, T9], T1, T2, T3, T4, T5, T6, T7, T8, T9 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8) Runnable[T1, T9] {
+	if composed(r1, r2, r3, r4, r5, r6, r7, r8) {
+		return newPipe[T1, T9](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5), stagesOf[T6, T7](r6), stagesOf[T7, T8](r7), stagesOf[T8, T9](r8)))
+	}
 	return chain8[T1, T2, T3, T4, T5, T6, T7, T8, T9]{
 		r1: r1,
 		r2: r2,
@@ -317,41 +541,71 @@ type chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10 any] struct {
 func (c chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) Invoke(in T1) (out T10, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
 	u, err := c.r5.Invoke(w)
 	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
 		return
 	}
 	v, err := c.r6.Invoke(u)
 	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
 		return
 	}
 	t, err := c.r7.Invoke(v)
 	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
 		return
 	}
 	s, err := c.r8.Invoke(t)
 	if err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
 		return
 	}
-	return c.r9.Invoke(s)
+	if out, err = c.r9.Invoke(s); err != nil {
+		err = stageError(nameOf(c.r9), 8, err)
+	}
+	return
+}
+
+func (c chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5), erase(c.r6), erase(c.r7), erase(c.r8), erase(c.r9)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) String() string {
+	return format(c.flatten())
 }
 
 // Chain9 takes 9 Runnable instances and returns a new Runnable instance that chains the nine together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain9[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], R6 Runnable[T6, T7], R7 Runnable[T7, T8], R8 Runnable[T8, T9], R9 Runnable[T9, T10], T1, T2, T3, T4, T5, T6, T7, T8, T9, T10 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8, r9 R9) Runnable[T1, T10] {
+	if composed(r1, r2, r3, r4, r5, r6, r7, r8, r9) {
+		return newPipe[T1, T10](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5), stagesOf[T6, T7](r6), stagesOf[T7, T8](r7), stagesOf[T8, T9](r8), stagesOf[T9, T10](r9)))
+	}
 	return chain9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]{
 		r1: r1,
 		r2: r2,
@@ -381,45 +635,76 @@ type chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11 any] struct {
 func (c chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) Invoke(in T1) (out T11, err error) {
 	x, err := c.r1.Invoke(in)
 	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
 		return
 	}
 	y, err := c.r2.Invoke(x)
 	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
 		return
 	}
 	z, err := c.r3.Invoke(y)
 	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
 		return
 	}
 	w, err := c.r4.Invoke(z)
 	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
 		return
 	}
 	u, err := c.r5.Invoke(w)
 	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
 		return
 	}
 	v, err := c.r6.Invoke(u)
 	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
 		return
 	}
 	t, err := c.r7.Invoke(v)
 	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
 		return
 	}
 	s, err := c.r8.Invoke(t)
 	if err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
 		return
 	}
 	r, err := c.r9.Invoke(s)
 	if err != nil {
+		err = stageError(nameOf(c.r9), 8, err)
 		return
 	}
-	return c.r10.Invoke(r)
+	if out, err = c.r10.Invoke(r); err != nil {
+		err = stageError(nameOf(c.r10), 9, err)
+	}
+	return
+}
+
+func (c chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) flatten() []stage {
+	return []stage{erase(c.r1), erase(c.r2), erase(c.r3), erase(c.r4), erase(c.r5), erase(c.r6), erase(c.r7), erase(c.r8), erase(c.r9), erase(c.r10)}
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) Describe() []StageInfo {
+	return describe(c.flatten())
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) String() string {
+	return format(c.flatten())
 }
 
 // Chain10 takes 10 Runnable instances and returns a new Runnable instance that chains the ten together.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+// If any of the Runnable instances is itself a chain or a Pipe, the stages are flattened into a single Pipe.
 func Chain10[R1 Runnable[T1, T2], R2 Runnable[T2, T3], R3 Runnable[T3, T4], R4 Runnable[T4, T5], R5 Runnable[T5, T6], R6 Runnable[T6, T7], R7 Runnable[T7, T8], R8 Runnable[T8, T9], R9 Runnable[T9, T10], R10 Runnable[T10, T11], T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8, r9 R9, r10 R10) Runnable[T1, T11] {
+	if composed(r1, r2, r3, r4, r5, r6, r7, r8, r9, r10) {
+		return newPipe[T1, T11](appendStages(stagesOf[T1, T2](r1), stagesOf[T2, T3](r2), stagesOf[T3, T4](r3), stagesOf[T4, T5](r4), stagesOf[T5, T6](r5), stagesOf[T6, T7](r6), stagesOf[T7, T8](r7), stagesOf[T8, T9](r8), stagesOf[T9, T10](r9), stagesOf[T10, T11](r10)))
+	}
 	return chain10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]{
 		r1:  r1,
 		r2:  r2,
@@ -52,3 +52,47 @@ func TestChain3(t *testing.T) {
 		t.Fatalf("expected: 5, got: %d", out)
 	}
 }
+
+func TestIdentityConst(t *testing.T) {
+	// Use Const as the default of a Switch and Identity as a pass-through stage.
+	r := chain.Chain2(chain.Identity[string](), chain.Switch(func(s string) string {
+		return s
+	}, map[string]chain.Runnable[string, int]{
+		"one": chain.Const[string](1),
+	}, chain.Const[string](0)))
+	for _, tt := range []struct {
+		in   string
+		want int
+	}{
+		{"one", 1},
+		{"two", 0},
+	} {
+		out, err := r.Invoke(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != tt.want {
+			t.Fatalf("expected: %d, got: %d", tt.want, out)
+		}
+	}
+}
+
+func TestToFunc(t *testing.T) {
+	r := chain.Chain2(chain.Func2(strconv.Atoi), chain.Func(func(i int) int {
+		return i * 2
+	}))
+	f := chain.ToFunc(r)
+	if out, err := f("21"); err != nil || out != 42 {
+		t.Fatalf("expected: 42, got: %d, %v", out, err)
+	}
+	must := chain.MustFunc(r)
+	if out := must("21"); out != 42 {
+		t.Fatalf("expected: 42, got: %d", out)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected: panic")
+		}
+	}()
+	must("x")
+}
@@ -0,0 +1,75 @@
+package chain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+type channelOptions[T any] struct {
+	onError func(context.Context, T, error)
+}
+
+// ChannelOption is a configuration option for the Channel function.
+type ChannelOption[T any] func(*channelOptions[T])
+
+// WithErrorHandler sets the function called with the input and the error when an invocation fails.
+// Failed inputs produce no output; by default they are dropped silently.
+func WithErrorHandler[T any](f func(context.Context, T, error)) ChannelOption[T] {
+	return func(o *channelOptions[T]) {
+		o.onError = f
+	}
+}
+
+func (o *channelOptions[T]) apply(opts []ChannelOption[T]) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// Channel starts a task that reads inputs from in, invokes r for each of them with the given number of workers,
+// and writes the outputs to out. The order of the outputs is not preserved when workers is greater than 1.
+//
+// The task completes when in is closed and drained, or when the context is canceled. The out channel is
+// closed when the task completes, so Channel must be the only writer of out.
+func Channel[T1, T2 any](ctx context.Context, r Runnable[T1, T2], in <-chan T1, out chan<- T2, workers int, options ...ChannelOption[T1]) spawn.Handle {
+	if workers <= 0 {
+		panic("non-positive workers for Channel")
+	}
+	var o channelOptions[T1]
+	o.apply(options)
+	return spawn.Run(ctx, func(ctx context.Context) {
+		defer close(out)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case x, ok := <-in:
+						if !ok {
+							return
+						}
+						y, err := r.Invoke(x)
+						if err != nil {
+							if o.onError != nil {
+								o.onError(ctx, x, err)
+							}
+							continue
+						}
+						select {
+						case out <- y:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
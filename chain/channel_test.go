@@ -0,0 +1,52 @@
+package chain_test
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestChannel(t *testing.T) {
+	in := make(chan string)
+	out := make(chan int)
+	var failed int32
+	h := chain.Channel(context.Background(), chain.Func2(strconv.Atoi), in, out, 3,
+		chain.WithErrorHandler(func(ctx context.Context, s string, err error) {
+			atomic.AddInt32(&failed, 1)
+		}),
+	)
+	go func() {
+		defer close(in)
+		for _, s := range []string{"1", "2", "x", "3"} {
+			in <- s
+		}
+	}()
+	var got []int
+	for v := range out {
+		got = append(got, v)
+	}
+	h.Join(context.Background())
+	sort.Ints(got)
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("expected: [1 2 3], got: %v", got)
+	}
+	if atomic.LoadInt32(&failed) != 1 {
+		t.Fatalf("expected: 1 failure, got: %d", failed)
+	}
+}
+
+func TestChannel_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := make(chan int)
+	h := chain.Channel(ctx, chain.Identity[int](), in, out, 2)
+	cancel()
+	h.Join(context.Background())
+	if _, ok := <-out; ok {
+		t.Fatal("expected: out closed after cancel")
+	}
+}
@@ -0,0 +1,817 @@
+package chain
+
+import "context"
+
+// RunnableContext is an interface that defines a single method, Invoke, which takes a context and a single input and returns a single output.
+type RunnableContext[T1, T2 any] interface {
+	Invoke(context.Context, T1) (T2, error)
+}
+
+// fnCtx is a type that wraps a function that takes a context and a single input and returns a single output.
+type fnCtx[T1, T2 any] func(context.Context, T1) T2
+
+func (f fnCtx[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	return f(ctx, in), nil
+}
+
+// FuncCtx is a function that takes a function and returns a RunnableContext instance that wraps the function.
+func FuncCtx[F ~func(context.Context, T1) T2, T1, T2 any](f F) RunnableContext[T1, T2] {
+	return fnCtx[T1, T2](f)
+}
+
+// fnCtx2 is a type that wraps a function that takes a context and a single input and returns a single output and an error.
+type fnCtx2[T1, T2 any] func(context.Context, T1) (T2, error)
+
+func (f fnCtx2[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	return f(ctx, in)
+}
+
+// FuncCtx2 is a function that takes a function and returns a RunnableContext instance that wraps the function.
+func FuncCtx2[F ~func(context.Context, T1) (T2, error), T1, T2 any](f F) RunnableContext[T1, T2] {
+	return fnCtx2[T1, T2](f)
+}
+
+// withContext is a type that wraps a Runnable instance as a RunnableContext instance.
+type withContext[T1, T2 any] struct {
+	r Runnable[T1, T2]
+}
+
+func (c withContext[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	return c.r.Invoke(in)
+}
+
+// Name returns the name of the wrapped Runnable instance (see Named), or empty.
+func (c withContext[T1, T2]) Name() string {
+	return nameOf(c.r)
+}
+
+// WithContext takes a Runnable instance and returns a RunnableContext instance that wraps it.
+// The returned instance does not invoke the Runnable if the context is already done.
+func WithContext[T1, T2 any](r Runnable[T1, T2]) RunnableContext[T1, T2] {
+	return withContext[T1, T2]{r: r}
+}
+
+// bound is a type that binds a RunnableContext instance to a context.
+type bound[T1, T2 any] struct {
+	ctx context.Context
+	r   RunnableContext[T1, T2]
+}
+
+func (b bound[T1, T2]) Invoke(in T1) (out T2, err error) {
+	return b.r.Invoke(b.ctx, in)
+}
+
+// Bind takes a context and a RunnableContext instance and returns a Runnable instance that invokes it with the context.
+func Bind[T1, T2 any](ctx context.Context, r RunnableContext[T1, T2]) Runnable[T1, T2] {
+	return bound[T1, T2]{ctx: ctx, r: r}
+}
+
+type chainCtx2[T1, T2, T3 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+}
+
+func (c chainCtx2[T1, T2, T3]) Invoke(ctx context.Context, in T1) (out T3, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r2.Invoke(ctx, x); err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx2[T1, T2, T3]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx2[T1, T2, T3]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx2 takes 2 RunnableContext instances and returns a new RunnableContext instance that chains the two together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx2[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], T1, T2, T3 any](r1 R1, r2 R2) RunnableContext[T1, T3] {
+	return chainCtx2[T1, T2, T3]{
+		r1: r1,
+		r2: r2,
+	}
+}
+
+type chainCtx3[T1, T2, T3, T4 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+}
+
+func (c chainCtx3[T1, T2, T3, T4]) Invoke(ctx context.Context, in T1) (out T4, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r3.Invoke(ctx, y); err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx3[T1, T2, T3, T4]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx3[T1, T2, T3, T4]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx3 takes 3 RunnableContext instances and returns a new RunnableContext instance that chains the three together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx3[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], T1, T2, T3, T4 any](r1 R1, r2 R2, r3 R3) RunnableContext[T1, T4] {
+	return chainCtx3[T1, T2, T3, T4]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+	}
+}
+
+type chainCtx4[T1, T2, T3, T4, T5 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+}
+
+func (c chainCtx4[T1, T2, T3, T4, T5]) Invoke(ctx context.Context, in T1) (out T5, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r4.Invoke(ctx, z); err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx4[T1, T2, T3, T4, T5]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx4[T1, T2, T3, T4, T5]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx4 takes 4 RunnableContext instances and returns a new RunnableContext instance that chains the four together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx4[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], T1, T2, T3, T4, T5 any](r1 R1, r2 R2, r3 R3, r4 R4) RunnableContext[T1, T5] {
+	return chainCtx4[T1, T2, T3, T4, T5]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+	}
+}
+
+type chainCtx5[T1, T2, T3, T4, T5, T6 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+	r5 RunnableContext[T5, T6]
+}
+
+func (c chainCtx5[T1, T2, T3, T4, T5, T6]) Invoke(ctx context.Context, in T1) (out T6, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r5.Invoke(ctx, w); err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx5[T1, T2, T3, T4, T5, T6]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx5[T1, T2, T3, T4, T5, T6]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx5 takes 5 RunnableContext instances and returns a new RunnableContext instance that chains the five together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx5[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], T1, T2, T3, T4, T5, T6 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5) RunnableContext[T1, T6] {
+	return chainCtx5[T1, T2, T3, T4, T5, T6]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+		r5: r5,
+	}
+}
+
+type chainCtx6[T1, T2, T3, T4, T5, T6, T7 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+	r5 RunnableContext[T5, T6]
+	r6 RunnableContext[T6, T7]
+}
+
+func (c chainCtx6[T1, T2, T3, T4, T5, T6, T7]) Invoke(ctx context.Context, in T1) (out T7, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	u, err := c.r5.Invoke(ctx, w)
+	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r6.Invoke(ctx, u); err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx6[T1, T2, T3, T4, T5, T6, T7]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5), stageInfo[T6, T7](c.r6)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx6[T1, T2, T3, T4, T5, T6, T7]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx6 takes 6 RunnableContext instances and returns a new RunnableContext instance that chains the six together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx6[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], R6 RunnableContext[T6, T7], T1, T2, T3, T4, T5, T6, T7 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6) RunnableContext[T1, T7] {
+	return chainCtx6[T1, T2, T3, T4, T5, T6, T7]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+		r5: r5,
+		r6: r6,
+	}
+}
+
+type chainCtx7[T1, T2, T3, T4, T5, T6, T7, T8 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+	r5 RunnableContext[T5, T6]
+	r6 RunnableContext[T6, T7]
+	r7 RunnableContext[T7, T8]
+}
+
+func (c chainCtx7[T1, T2, T3, T4, T5, T6, T7, T8]) Invoke(ctx context.Context, in T1) (out T8, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	u, err := c.r5.Invoke(ctx, w)
+	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	v, err := c.r6.Invoke(ctx, u)
+	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r7.Invoke(ctx, v); err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx7[T1, T2, T3, T4, T5, T6, T7, T8]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5), stageInfo[T6, T7](c.r6), stageInfo[T7, T8](c.r7)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx7[T1, T2, T3, T4, T5, T6, T7, T8]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx7 takes 7 RunnableContext instances and returns a new RunnableContext instance that chains the seven together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx7[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], R6 RunnableContext[T6, T7], R7 RunnableContext[T7, T8], T1, T2, T3, T4, T5, T6, T7, T8 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7) RunnableContext[T1, T8] {
+	return chainCtx7[T1, T2, T3, T4, T5, T6, T7, T8]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+		r5: r5,
+		r6: r6,
+		r7: r7,
+	}
+}
+
+type chainCtx8[T1, T2, T3, T4, T5, T6, T7, T8, T9 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+	r5 RunnableContext[T5, T6]
+	r6 RunnableContext[T6, T7]
+	r7 RunnableContext[T7, T8]
+	r8 RunnableContext[T8, T9]
+}
+
+func (c chainCtx8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) Invoke(ctx context.Context, in T1) (out T9, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	u, err := c.r5.Invoke(ctx, w)
+	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	v, err := c.r6.Invoke(ctx, u)
+	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	t, err := c.r7.Invoke(ctx, v)
+	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r8.Invoke(ctx, t); err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5), stageInfo[T6, T7](c.r6), stageInfo[T7, T8](c.r7), stageInfo[T8, T9](c.r8)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx8[T1, T2, T3, T4, T5, T6, T7, T8, T9]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx8 takes 8 RunnableContext instances and returns a new RunnableContext instance that chains the eight together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx8[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], R6 RunnableContext[T6, T7], R7 RunnableContext[T7, T8], R8 RunnableContext[T8, T9], T1, T2, T3, T4, T5, T6, T7, T8, T9 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8) RunnableContext[T1, T9] {
+	return chainCtx8[T1, T2, T3, T4, T5, T6, T7, T8, T9]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+		r5: r5,
+		r6: r6,
+		r7: r7,
+		r8: r8,
+	}
+}
+
+type chainCtx9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10 any] struct {
+	r1 RunnableContext[T1, T2]
+	r2 RunnableContext[T2, T3]
+	r3 RunnableContext[T3, T4]
+	r4 RunnableContext[T4, T5]
+	r5 RunnableContext[T5, T6]
+	r6 RunnableContext[T6, T7]
+	r7 RunnableContext[T7, T8]
+	r8 RunnableContext[T8, T9]
+	r9 RunnableContext[T9, T10]
+}
+
+func (c chainCtx9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) Invoke(ctx context.Context, in T1) (out T10, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	u, err := c.r5.Invoke(ctx, w)
+	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	v, err := c.r6.Invoke(ctx, u)
+	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	t, err := c.r7.Invoke(ctx, v)
+	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	s, err := c.r8.Invoke(ctx, t)
+	if err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r9.Invoke(ctx, s); err != nil {
+		err = stageError(nameOf(c.r9), 8, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5), stageInfo[T6, T7](c.r6), stageInfo[T7, T8](c.r7), stageInfo[T8, T9](c.r8), stageInfo[T9, T10](c.r9)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx9 takes 9 RunnableContext instances and returns a new RunnableContext instance that chains the nine together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx9[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], R6 RunnableContext[T6, T7], R7 RunnableContext[T7, T8], R8 RunnableContext[T8, T9], R9 RunnableContext[T9, T10], T1, T2, T3, T4, T5, T6, T7, T8, T9, T10 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8, r9 R9) RunnableContext[T1, T10] {
+	return chainCtx9[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]{
+		r1: r1,
+		r2: r2,
+		r3: r3,
+		r4: r4,
+		r5: r5,
+		r6: r6,
+		r7: r7,
+		r8: r8,
+		r9: r9,
+	}
+}
+
+type chainCtx10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11 any] struct {
+	r1  RunnableContext[T1, T2]
+	r2  RunnableContext[T2, T3]
+	r3  RunnableContext[T3, T4]
+	r4  RunnableContext[T4, T5]
+	r5  RunnableContext[T5, T6]
+	r6  RunnableContext[T6, T7]
+	r7  RunnableContext[T7, T8]
+	r8  RunnableContext[T8, T9]
+	r9  RunnableContext[T9, T10]
+	r10 RunnableContext[T10, T11]
+}
+
+func (c chainCtx10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) Invoke(ctx context.Context, in T1) (out T11, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	x, err := c.r1.Invoke(ctx, in)
+	if err != nil {
+		err = stageError(nameOf(c.r1), 0, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	y, err := c.r2.Invoke(ctx, x)
+	if err != nil {
+		err = stageError(nameOf(c.r2), 1, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	z, err := c.r3.Invoke(ctx, y)
+	if err != nil {
+		err = stageError(nameOf(c.r3), 2, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	w, err := c.r4.Invoke(ctx, z)
+	if err != nil {
+		err = stageError(nameOf(c.r4), 3, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	u, err := c.r5.Invoke(ctx, w)
+	if err != nil {
+		err = stageError(nameOf(c.r5), 4, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	v, err := c.r6.Invoke(ctx, u)
+	if err != nil {
+		err = stageError(nameOf(c.r6), 5, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	t, err := c.r7.Invoke(ctx, v)
+	if err != nil {
+		err = stageError(nameOf(c.r7), 6, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	s, err := c.r8.Invoke(ctx, t)
+	if err != nil {
+		err = stageError(nameOf(c.r8), 7, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	r, err := c.r9.Invoke(ctx, s)
+	if err != nil {
+		err = stageError(nameOf(c.r9), 8, err)
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if out, err = c.r10.Invoke(ctx, r); err != nil {
+		err = stageError(nameOf(c.r10), 9, err)
+	}
+	return
+}
+
+// Describe returns the description of each stage of the chain.
+func (c chainCtx10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) Describe() []StageInfo {
+	return []StageInfo{stageInfo[T1, T2](c.r1), stageInfo[T2, T3](c.r2), stageInfo[T3, T4](c.r3), stageInfo[T4, T5](c.r4), stageInfo[T5, T6](c.r5), stageInfo[T6, T7](c.r6), stageInfo[T7, T8](c.r7), stageInfo[T8, T9](c.r8), stageInfo[T9, T10](c.r9), stageInfo[T10, T11](c.r10)}
+}
+
+// String returns the description of the stages of the chain, separated by " | ".
+func (c chainCtx10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) String() string {
+	return formatInfos(c.Describe())
+}
+
+// ChainCtx10 takes 10 RunnableContext instances and returns a new RunnableContext instance that chains the ten together.
+// The context is checked before each stage, so a canceled context stops the chain between stages.
+// An error returned by a stage is wrapped with the name (see Named) or 0-based index of the stage.
+func ChainCtx10[R1 RunnableContext[T1, T2], R2 RunnableContext[T2, T3], R3 RunnableContext[T3, T4], R4 RunnableContext[T4, T5], R5 RunnableContext[T5, T6], R6 RunnableContext[T6, T7], R7 RunnableContext[T7, T8], R8 RunnableContext[T8, T9], R9 RunnableContext[T9, T10], R10 RunnableContext[T10, T11], T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11 any](r1 R1, r2 R2, r3 R3, r4 R4, r5 R5, r6 R6, r7 R7, r8 R8, r9 R9, r10 R10) RunnableContext[T1, T11] {
+	return chainCtx10[T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]{
+		r1:  r1,
+		r2:  r2,
+		r3:  r3,
+		r4:  r4,
+		r5:  r5,
+		r6:  r6,
+		r7:  r7,
+		r8:  r8,
+		r9:  r9,
+		r10: r10,
+	}
+}
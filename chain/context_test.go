@@ -0,0 +1,102 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestChainCtx2(t *testing.T) {
+	r1 := chain.FuncCtx(func(ctx context.Context, s string) int {
+		return len(s)
+	})
+	r2 := chain.WithContext(chain.Func(func(i int) string {
+		return strconv.Itoa(i)
+	}))
+	r := chain.ChainCtx2(r1, r2)
+	out, err := r.Invoke(context.Background(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "5" {
+		t.Fatalf("expected: 5, got: %s", out)
+	}
+}
+
+func TestChainCtx3_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var called bool
+	// The first stage cancels the context, so the remaining stages must not run.
+	r1 := chain.FuncCtx(func(ctx context.Context, s string) int {
+		cancel()
+		return len(s)
+	})
+	r2 := chain.FuncCtx(func(ctx context.Context, i int) int {
+		called = true
+		return i
+	})
+	r3 := chain.FuncCtx2(func(ctx context.Context, i int) (string, error) {
+		called = true
+		return strconv.Itoa(i), nil
+	})
+	r := chain.ChainCtx3(r1, r2, r3)
+	if _, err := r.Invoke(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected: %v, got: %v", context.Canceled, err)
+	}
+	if called {
+		t.Fatal("expected: stages after cancellation not called")
+	}
+}
+
+func TestBind(t *testing.T) {
+	r := chain.Bind(context.Background(), chain.FuncCtx(func(ctx context.Context, s string) int {
+		return len(s)
+	}))
+	out, err := chain.Chain2(r, chain.Func(strconv.Itoa)).Invoke("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "5" {
+		t.Fatalf("expected: 5, got: %s", out)
+	}
+}
+
+func TestChainCtx_StageError(t *testing.T) {
+	errBad := errors.New("bad")
+	length := chain.FuncCtx(func(ctx context.Context, s string) int {
+		return len(s)
+	})
+	fail := chain.FuncCtx2(func(ctx context.Context, i int) (string, error) {
+		return "", errBad
+	})
+
+	_, err := chain.ChainCtx2(length, fail).Invoke(context.Background(), "hello")
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected: %v, got: %v", errBad, err)
+	}
+	if want := "stage 1: bad"; err.Error() != want {
+		t.Fatalf("expected: %s, got: %s", want, err)
+	}
+
+	named := chain.WithContext(chain.Named("format", chain.Func2(func(i int) (int, error) {
+		return 0, errBad
+	})))
+	_, err = chain.ChainCtx3(length, named, chain.WithContext(chain.Func(strconv.Itoa))).Invoke(context.Background(), "hello")
+	if want := `stage "format": bad`; err == nil || err.Error() != want {
+		t.Fatalf("expected: %s, got: %v", want, err)
+	}
+
+	want := `string -> int | "format": int -> int | int -> string`
+	r := chain.ChainCtx3(length, named, chain.WithContext(chain.Func(strconv.Itoa)))
+	if s := chain.DescribeCtx(r); len(s) != 3 || s[1].Name != "format" {
+		t.Fatalf("unexpected stage infos: %v", s)
+	}
+	if s := r.(fmt.Stringer).String(); s != want {
+		t.Fatalf("expected: %s, got: %s", want, s)
+	}
+}
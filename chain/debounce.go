@@ -0,0 +1,120 @@
+package chain
+
+import (
+	"sync"
+	"time"
+)
+
+// debounceBatch is a group of invocations coalesced into a single invocation.
+type debounceBatch[T1, T2 any] struct {
+	in    T1
+	timer *time.Timer
+	done  chan struct{}
+	out   T2
+	err   error
+}
+
+type debouncer[T1, T2 any] struct {
+	r Runnable[T1, T2]
+	d time.Duration
+
+	mu    sync.Mutex
+	batch *debounceBatch[T1, T2]
+}
+
+func (d *debouncer[T1, T2]) Invoke(in T1) (out T2, err error) {
+	d.mu.Lock()
+	b := d.batch
+	if b == nil {
+		b = &debounceBatch[T1, T2]{done: make(chan struct{})}
+		d.batch = b
+		b.timer = time.AfterFunc(d.d, func() { d.fire(b) })
+	} else {
+		b.timer.Reset(d.d)
+	}
+	b.in = in
+	d.mu.Unlock()
+	<-b.done
+	return b.out, b.err
+}
+
+func (d *debouncer[T1, T2]) fire(b *debounceBatch[T1, T2]) {
+	d.mu.Lock()
+	if d.batch != b {
+		// The batch has already been fired by an earlier expiration of its timer.
+		d.mu.Unlock()
+		return
+	}
+	d.batch = nil
+	d.mu.Unlock()
+	b.out, b.err = d.r.Invoke(b.in)
+	close(b.done)
+}
+
+// Debounce takes a Runnable instance and returns a new Runnable instance that coalesces rapid invocations:
+// r is invoked only after no invocation has happened for the duration d, with the input of the latest
+// invocation. All coalesced invocations block until then and return the same result.
+func Debounce[T1, T2 any](r Runnable[T1, T2], d time.Duration) Runnable[T1, T2] {
+	if d <= 0 {
+		panic("non-positive duration for Debounce")
+	}
+	return &debouncer[T1, T2]{r: r, d: d}
+}
+
+// throttleCall is a real invocation of a throttled Runnable.
+type throttleCall[T2 any] struct {
+	start time.Time
+	done  chan struct{}
+	out   T2
+	err   error
+}
+
+type throttler[T1, T2 any] struct {
+	r Runnable[T1, T2]
+	d time.Duration
+
+	mu       sync.Mutex
+	last     *throttleCall[T2] // the latest invocation
+	finished *throttleCall[T2] // the latest finished invocation
+}
+
+func (t *throttler[T1, T2]) Invoke(in T1) (out T2, err error) {
+	t.mu.Lock()
+	if now := time.Now(); t.last == nil || now.Sub(t.last.start) >= t.d {
+		c := &throttleCall[T2]{start: now, done: make(chan struct{})}
+		t.last = c
+		t.mu.Unlock()
+		t.invoke(c, in)
+		return c.out, c.err
+	}
+	c := t.finished
+	if c == nil {
+		// The first invocation has not finished yet: wait for its result.
+		c = t.last
+	}
+	t.mu.Unlock()
+	<-c.done
+	return c.out, c.err
+}
+
+func (t *throttler[T1, T2]) invoke(c *throttleCall[T2], in T1) {
+	defer func() {
+		t.mu.Lock()
+		if t.finished == nil || t.finished.start.Before(c.start) {
+			t.finished = c
+		}
+		t.mu.Unlock()
+		close(c.done)
+	}()
+	c.out, c.err = t.r.Invoke(in)
+}
+
+// Throttle takes a Runnable instance and returns a new Runnable instance that invokes r at most once per
+// duration d. Invocations within d of the start of the last real invocation are dropped and return the
+// result of the latest finished one without waiting for a running one, unless none has finished yet.
+func Throttle[T1, T2 any](r Runnable[T1, T2], d time.Duration) Runnable[T1, T2] {
+	if d <= 0 {
+		panic("non-positive duration for Throttle")
+	}
+	return &throttler[T1, T2]{r: r, d: d}
+}
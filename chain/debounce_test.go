@@ -0,0 +1,136 @@
+package chain_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestDebounce(t *testing.T) {
+	var calls int32
+	r := chain.Debounce(chain.Func(func(i int) int {
+		atomic.AddInt32(&calls, 1)
+		return i * 10
+	}), 20*time.Millisecond)
+	var wg sync.WaitGroup
+	results := make([]int, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = r.Invoke(i + 1)
+		}()
+		time.Sleep(2 * time.Millisecond)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected: 1 call, got: %d", n)
+	}
+	for _, v := range results {
+		if v != results[0] || v%10 != 0 || v == 0 {
+			t.Fatalf("expected: same result for coalesced calls, got: %v", results)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var calls int
+	r := chain.Throttle(chain.Func(func(i int) int {
+		calls++
+		return i
+	}), 50*time.Millisecond)
+	if out, _ := r.Invoke(1); out != 1 {
+		t.Fatalf("expected: 1, got: %d", out)
+	}
+	if out, _ := r.Invoke(2); out != 1 {
+		t.Fatalf("expected: last computed value 1, got: %d", out)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if out, _ := r.Invoke(3); out != 3 {
+		t.Fatalf("expected: 3, got: %d", out)
+	}
+	if calls != 2 {
+		t.Fatalf("expected: 2 calls, got: %d", calls)
+	}
+}
+
+func TestThrottle_Slow(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	r := chain.Throttle(chain.Func(func(i int) int {
+		if calls.Add(1) == 2 {
+			close(started)
+			<-release
+		}
+		return i
+	}), 50*time.Millisecond)
+	if out, _ := r.Invoke(1); out != 1 {
+		t.Fatalf("expected: 1, got: %d", out)
+	}
+	time.Sleep(60 * time.Millisecond)
+	result := make(chan int)
+	go func() {
+		out, _ := r.Invoke(2)
+		result <- out
+	}()
+	<-started
+
+	// A dropped invocation returns the last finished result without waiting for the slow one.
+	dropped := make(chan int)
+	go func() {
+		out, _ := r.Invoke(3)
+		dropped <- out
+	}()
+	select {
+	case out := <-dropped:
+		if out != 1 {
+			t.Fatalf("expected: last finished value 1, got: %d", out)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected: dropped invocation not blocked by the running one")
+	}
+	close(release)
+	if out := <-result; out != 2 {
+		t.Fatalf("expected: 2, got: %d", out)
+	}
+	if out, _ := r.Invoke(4); out != 2 {
+		t.Fatalf("expected: last finished value 2, got: %d", out)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected: 2 calls, got: %d", n)
+	}
+}
+
+func TestThrottle_First(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	r := chain.Throttle(chain.Func(func(i int) int {
+		calls.Add(1)
+		close(started)
+		<-release
+		return i
+	}), time.Minute)
+	var wg sync.WaitGroup
+	outs := make([]int, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outs[0], _ = r.Invoke(1)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outs[1], _ = r.Invoke(2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	// Without a finished result, a dropped invocation waits for the first one.
+	if outs[0] != 1 || outs[1] != 1 || calls.Load() != 1 {
+		t.Fatalf("expected: both invocations returning 1 from 1 call, got: %v from %d calls", outs, calls.Load())
+	}
+}
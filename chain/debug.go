@@ -0,0 +1,15 @@
+package chain
+
+// Debug takes a Runnable instance and a sink and returns a new Runnable instance that calls the sink with the
+// 0-based index, input, output and error of each stage after it is invoked. If r is a chain created by ChainN
+// or a Pipe, every intermediate value is recorded; otherwise r is recorded as a single stage.
+func Debug[T1, T2 any](r Runnable[T1, T2], sink func(stage int, in, out any, err error)) Runnable[T1, T2] {
+	if sink == nil {
+		panic("nil sink for Debug")
+	}
+	return Wrap(r, func(stage int, in any, next Invoker) (any, error) {
+		out, err := next(in)
+		sink(stage, in, out, err)
+		return out, err
+	})
+}
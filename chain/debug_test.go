@@ -0,0 +1,31 @@
+package chain_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestDebug(t *testing.T) {
+	var records []string
+	r := chain.Debug(chain.Chain3(chain.Func(func(s string) int {
+		return len(s)
+	}), chain.Func(func(i int) int {
+		return i * 2
+	}), chain.Func(strconv.Itoa)), func(stage int, in, out any, err error) {
+		records = append(records, fmt.Sprintf("%d: %v -> %v (%v)", stage, in, out, err))
+	})
+	if _, err := r.Invoke("hello"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"0: hello -> 5 (<nil>)",
+		"1: 5 -> 10 (<nil>)",
+		"2: 10 -> 10 (<nil>)",
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Fatalf("expected: %v, got: %v", want, records)
+	}
+}
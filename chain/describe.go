@@ -0,0 +1,78 @@
+package chain
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StageInfo describes a stage of a composed chain.
+type StageInfo struct {
+	// Name is the name of the stage (see Named), or empty.
+	Name string
+	// In is the input type of the stage.
+	In reflect.Type
+	// Out is the output type of the stage.
+	Out reflect.Type
+}
+
+// String returns the description of the stage, for example: "atoi": string -> int
+func (s StageInfo) String() string {
+	if s.Name == "" {
+		return s.In.String() + " -> " + s.Out.String()
+	}
+	return strconv.Quote(s.Name) + ": " + s.In.String() + " -> " + s.Out.String()
+}
+
+func describe(stages []stage) []StageInfo {
+	infos := make([]StageInfo, len(stages))
+	for i, s := range stages {
+		infos[i] = StageInfo{Name: s.name, In: s.in, Out: s.out}
+	}
+	return infos
+}
+
+func format(stages []stage) string {
+	return formatInfos(describe(stages))
+}
+
+func formatInfos(infos []StageInfo) string {
+	var sb strings.Builder
+	for i, s := range infos {
+		if i > 0 {
+			sb.WriteString(" | ")
+		}
+		sb.WriteString(s.String())
+	}
+	return sb.String()
+}
+
+// Describe returns the description of each stage of the Runnable instance r. If r is a chain created
+// by ChainN or a Pipe, every stage is described; otherwise r is described as a single stage.
+func Describe[T1, T2 any](r Runnable[T1, T2]) []StageInfo {
+	return describe(stagesOf(r))
+}
+
+// stageInfo describes a single stage that takes T1 and returns T2.
+func stageInfo[T1, T2 any](r any) StageInfo {
+	return StageInfo{Name: nameOf(r), In: reflect.TypeFor[T1](), Out: reflect.TypeFor[T2]()}
+}
+
+// DescribeCtx is like Describe for RunnableContext instances. If r is a chain created by ChainCtxN,
+// every stage is described; otherwise r is described as a single stage.
+func DescribeCtx[T1, T2 any](r RunnableContext[T1, T2]) []StageInfo {
+	if d, ok := r.(interface{ Describe() []StageInfo }); ok {
+		return d.Describe()
+	}
+	return []StageInfo{stageInfo[T1, T2](r)}
+}
+
+// Describe returns the description of each stage of the Pipe.
+func (p *Pipe[T1, T2]) Describe() []StageInfo {
+	return describe(p.stages)
+}
+
+// String returns the description of the stages of the Pipe, separated by " | ".
+func (p *Pipe[T1, T2]) String() string {
+	return format(p.stages)
+}
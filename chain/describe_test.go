@@ -0,0 +1,28 @@
+package chain_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestDescribe(t *testing.T) {
+	r := chain.Chain2(chain.Named("atoi", chain.Func2(strconv.Atoi)), chain.Func(strconv.Itoa))
+	want := `"atoi": string -> int | int -> string`
+	if s := fmt.Sprint(r); s != want {
+		t.Fatalf("expected: %s, got: %s", want, s)
+	}
+	p := chain.Then(chain.New(r), chain.Func(func(s string) []byte {
+		return []byte(s)
+	}))
+	if s := p.String(); s != want+" | string -> []uint8" {
+		t.Fatalf("unexpected description: %s", s)
+	}
+	infos := chain.Describe[string, []byte](p)
+	if len(infos) != 3 || infos[0].Name != "atoi" || infos[2].Out != reflect.TypeFor[[]byte]() {
+		t.Fatalf("unexpected stage infos: %v", infos)
+	}
+}
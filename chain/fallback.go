@@ -0,0 +1,30 @@
+package chain
+
+import "errors"
+
+type fallback[T1, T2 any] []Runnable[T1, T2]
+
+func (f fallback[T1, T2]) Invoke(in T1) (out T2, err error) {
+	var errs []error
+	for _, r := range f {
+		if out, err = r.Invoke(in); err == nil {
+			return
+		}
+		errs = append(errs, err)
+	}
+	var zero T2
+	return zero, errors.Join(errs...)
+}
+
+// Fallback takes one or more Runnable instances and returns a new Runnable instance that tries them in order
+// and returns the first successful result. If all of them fail, the errors are joined with errors.Join.
+func Fallback[T1, T2 any](primary Runnable[T1, T2], alternatives ...Runnable[T1, T2]) Runnable[T1, T2] {
+	f := make(fallback[T1, T2], 0, len(alternatives)+1)
+	f = append(f, primary)
+	return append(f, alternatives...)
+}
+
+// Or is an alias for Fallback with exactly two Runnable instances.
+func Or[T1, T2 any](r1, r2 Runnable[T1, T2]) Runnable[T1, T2] {
+	return Fallback(r1, r2)
+}
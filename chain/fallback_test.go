@@ -0,0 +1,38 @@
+package chain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestFallback(t *testing.T) {
+	errFirst := errors.New("first")
+	r := chain.Fallback(chain.Func2(func(s string) (int, error) {
+		return 0, errFirst
+	}), chain.Func(func(s string) int {
+		return len(s)
+	}))
+	out, err := r.Invoke("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 5 {
+		t.Fatalf("expected: 5, got: %d", out)
+	}
+}
+
+func TestFallback_AllFail(t *testing.T) {
+	err1 := errors.New("first")
+	err2 := errors.New("second")
+	r := chain.Or(chain.Func2(func(s string) (int, error) {
+		return 0, err1
+	}), chain.Func2(func(s string) (int, error) {
+		return 0, err2
+	}))
+	_, err := r.Invoke("hello")
+	if !errors.Is(err, err1) || !errors.Is(err, err2) {
+		t.Fatalf("expected: both errors, got: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package chain
+
+import "errors"
+
+// ErrFiltered is the error returned by FilterOne when the predicate rejects the input.
+// It short-circuits the rest of the chain; use errors.Is to distinguish it from real failures.
+var ErrFiltered = errors.New("filtered")
+
+// filter is a type that wraps a predicate and keeps the elements of a slice that satisfy it.
+type filter[T any] func(T) bool
+
+func (f filter[T]) Invoke(in []T) (out []T, err error) {
+	if in == nil {
+		return nil, nil
+	}
+	out = make([]T, 0, len(in))
+	for _, x := range in {
+		if f(x) {
+			out = append(out, x)
+		}
+	}
+	return out, nil
+}
+
+// Filter takes a predicate and returns a Runnable instance that keeps the elements of a slice that satisfy it.
+// The input slice is not modified.
+func Filter[F ~func(T) bool, T any](pred F) Runnable[[]T, []T] {
+	return filter[T](pred)
+}
+
+// filterOne is a type that wraps a predicate and passes through the input only if it satisfies it.
+type filterOne[T any] func(T) bool
+
+func (f filterOne[T]) Invoke(in T) (out T, err error) {
+	if !f(in) {
+		return out, ErrFiltered
+	}
+	return in, nil
+}
+
+// FilterOne takes a predicate and returns a Runnable instance that passes through the input if it satisfies the predicate,
+// or returns ErrFiltered otherwise.
+func FilterOne[F ~func(T) bool, T any](pred F) Runnable[T, T] {
+	return filterOne[T](pred)
+}
@@ -0,0 +1,46 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestFilter(t *testing.T) {
+	r := chain.Filter(func(i int) bool {
+		return i%2 == 0
+	})
+	out, err := r.Invoke([]int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0] != 2 || out[1] != 4 {
+		t.Fatalf("expected: [2 4], got: %v", out)
+	}
+}
+
+func TestFilterOne(t *testing.T) {
+	var called bool
+	r := chain.Chain2(chain.FilterOne(func(i int) bool {
+		return i > 0
+	}), chain.Func(func(i int) string {
+		called = true
+		return strconv.Itoa(i)
+	}))
+	out, err := r.Invoke(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "1" {
+		t.Fatalf("expected: 1, got: %s", out)
+	}
+	called = false
+	if _, err := r.Invoke(-1); !errors.Is(err, chain.ErrFiltered) {
+		t.Fatalf("expected: %v, got: %v", chain.ErrFiltered, err)
+	}
+	if called {
+		t.Fatal("expected: stage after filter not called")
+	}
+}
@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"errors"
+	"sync"
+)
+
+type forEachOptions struct {
+	failFast bool
+}
+
+// ForEachOption is a configuration option for the ForEach function.
+type ForEachOption func(*forEachOptions)
+
+// WithFailFast specifies whether ForEach stops starting new invocations after the first error.
+func WithFailFast(failFast bool) ForEachOption {
+	return func(o *forEachOptions) {
+		o.failFast = failFast
+	}
+}
+
+// ForEach invokes the Runnable instance r for each input with at most workers concurrent invocations,
+// and returns the errors of all failed invocations joined with errors.Join, or nil.
+// If WithFailFast is set, no new invocations are started after the first error.
+func ForEach[T any](r Runnable[T, struct{}], inputs []T, workers int, options ...ForEachOption) error {
+	if workers <= 0 {
+		panic("non-positive workers for ForEach")
+	}
+	var o forEachOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   []error
+		failed bool
+		sem    = make(chan struct{}, workers)
+	)
+	for _, x := range inputs {
+		sem <- struct{}{}
+		if o.failFast {
+			mu.Lock()
+			stop := failed
+			mu.Unlock()
+			if stop {
+				<-sem
+				break
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if _, err := r.Invoke(x); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				failed = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestForEach(t *testing.T) {
+	var sum int64
+	err := chain.ForEach(chain.Func2(func(i int) (struct{}, error) {
+		if i%5 == 0 {
+			return struct{}{}, errors.New("multiple of 5: " + strconv.Itoa(i))
+		}
+		atomic.AddInt64(&sum, int64(i))
+		return struct{}{}, nil
+	}), []int{1, 2, 3, 5, 10}, 2)
+	if err == nil {
+		t.Fatal("expected: error")
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+		t.Fatalf("expected: 2 errors, got: %d", n)
+	}
+	if sum != 6 {
+		t.Fatalf("expected: sum 6, got: %d", sum)
+	}
+}
+
+func TestForEach_FailFast(t *testing.T) {
+	var calls int32
+	err := chain.ForEach(chain.Func2(func(i int) (struct{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return struct{}{}, errors.New("bad")
+	}), make([]int, 100), 1, chain.WithFailFast(true))
+	if err == nil {
+		t.Fatal("expected: error")
+	}
+	if n := atomic.LoadInt32(&calls); n > 2 {
+		t.Fatalf("expected: at most 2 invocations, got: %d", n)
+	}
+}
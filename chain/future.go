@@ -0,0 +1,55 @@
+package chain
+
+import "context"
+
+// Future represents the result of an asynchronous invocation.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newFuture[T any](f func() (T, error)) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer close(fut.done)
+		fut.value, fut.err = f()
+	}()
+	return fut
+}
+
+// Done returns a channel that is closed when the invocation completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await blocks until the invocation completes or the context is done, and returns the result.
+// If the context is done first, the context error is returned and the invocation keeps running.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Async takes a Runnable instance and returns a function that invokes it in a new goroutine
+// and returns a Future for the result.
+func Async[T1, T2 any](r Runnable[T1, T2]) func(T1) *Future[T2] {
+	return func(in T1) *Future[T2] {
+		return newFuture(func() (T2, error) {
+			return r.Invoke(in)
+		})
+	}
+}
+
+// AsyncCtx is like Async but for RunnableContext instances. The context is passed to the invocation.
+func AsyncCtx[T1, T2 any](r RunnableContext[T1, T2]) func(context.Context, T1) *Future[T2] {
+	return func(ctx context.Context, in T1) *Future[T2] {
+		return newFuture(func() (T2, error) {
+			return r.Invoke(ctx, in)
+		})
+	}
+}
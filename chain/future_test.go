@@ -0,0 +1,42 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestAsync(t *testing.T) {
+	f := chain.Async(chain.Func(func(s string) int {
+		return len(s)
+	}))
+	f1, f2 := f("hello"), f("hi")
+	out, err := f1.Await(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 5 {
+		t.Fatalf("expected: 5, got: %d", out)
+	}
+	<-f2.Done()
+	if out, _ := f2.Await(context.Background()); out != 2 {
+		t.Fatalf("expected: 2, got: %d", out)
+	}
+}
+
+func TestAsync_AwaitTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	f := chain.Async(chain.Func(func(s string) int {
+		<-release
+		return len(s)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := f("hello").Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,64 @@
+package chain
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gopherd/exp/httputil"
+)
+
+// DecodeJSON decodes the JSON body of the request into a value of type T.
+func DecodeJSON[T any](r *http.Request) (T, error) {
+	var v T
+	err := json.NewDecoder(r.Body).Decode(&v)
+	return v, err
+}
+
+// EncodeJSON writes the value v wrapped in an httputil.Response as JSON.
+func EncodeJSON[T any](w http.ResponseWriter, v T) error {
+	return writeJSON(w, http.StatusOK, httputil.Result(v))
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(v)
+}
+
+type handler[T1, T2 any] struct {
+	r      Runnable[T1, T2]
+	decode func(*http.Request) (T1, error)
+	encode func(http.ResponseWriter, T2) error
+}
+
+func (h handler[T1, T2]) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	in, err := h.decode(req)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, httputil.Result(err))
+		return
+	}
+	out, err := h.r.Invoke(in)
+	if err != nil {
+		writeJSON(w, http.StatusOK, httputil.Result(err))
+		return
+	}
+	if err := h.encode(w, out); err != nil {
+		slog.Warn("failed to encode response", "error", err, "path", req.URL.Path)
+	}
+}
+
+// HTTPHandler takes a Runnable instance and returns an http.Handler that decodes the request with decode,
+// invokes r, and writes the output with encode. If decode or encode is nil, DecodeJSON or EncodeJSON is used.
+//
+// Errors are written as an httputil.Response: a decode error with status 400, and an error returned
+// by r with status 200, like the JSON helpers of the httputil packages.
+func HTTPHandler[T1, T2 any](r Runnable[T1, T2], decode func(*http.Request) (T1, error), encode func(http.ResponseWriter, T2) error) http.Handler {
+	if decode == nil {
+		decode = DecodeJSON[T1]
+	}
+	if encode == nil {
+		encode = EncodeJSON[T2]
+	}
+	return handler[T1, T2]{r: r, decode: decode, encode: encode}
+}
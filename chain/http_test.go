@@ -0,0 +1,53 @@
+package chain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+	"github.com/gopherd/exp/httputil"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+func TestHTTPHandler(t *testing.T) {
+	h := chain.HTTPHandler(chain.Func2(func(req greetRequest) (string, error) {
+		if req.Name == "" {
+			return "", errors.New("empty name")
+		}
+		return "hello " + req.Name, nil
+	}), nil, nil)
+
+	for _, tt := range []struct {
+		body   string
+		status int
+		data   any
+		hasErr bool
+	}{
+		{`{"name":"gopher"}`, http.StatusOK, "hello gopher", false},
+		{`{"name":""}`, http.StatusOK, nil, true},
+		{`{`, http.StatusBadRequest, nil, true},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/greet", strings.NewReader(tt.body)))
+		if w.Code != tt.status {
+			t.Fatalf("expected: status %d, got: %d", tt.status, w.Code)
+		}
+		var resp httputil.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if (resp.Error.Message != "") != tt.hasErr {
+			t.Fatalf("unexpected error in response: %q", resp.Error.Message)
+		}
+		if resp.Data != tt.data {
+			t.Fatalf("expected: %v, got: %v", tt.data, resp.Data)
+		}
+	}
+}
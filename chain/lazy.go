@@ -0,0 +1,25 @@
+package chain
+
+import "sync"
+
+type lazy[T1, T2 any] struct {
+	build func() (Runnable[T1, T2], error)
+}
+
+func (l lazy[T1, T2]) Invoke(in T1) (out T2, err error) {
+	r, err := l.build()
+	if err != nil {
+		return
+	}
+	return r.Invoke(in)
+}
+
+// Lazy takes a function that builds a Runnable instance and returns a new Runnable instance that calls it
+// on the first invocation only. Later invocations reuse the built Runnable instance, or return the error
+// of the build function if it failed.
+func Lazy[T1, T2 any](build func() (Runnable[T1, T2], error)) Runnable[T1, T2] {
+	if build == nil {
+		panic("nil build function for Lazy")
+	}
+	return lazy[T1, T2]{build: sync.OnceValues(build)}
+}
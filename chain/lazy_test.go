@@ -0,0 +1,40 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestLazy(t *testing.T) {
+	var builds int
+	r := chain.Lazy(func() (chain.Runnable[int, string], error) {
+		builds++
+		return chain.Func(strconv.Itoa), nil
+	})
+	if builds != 0 {
+		t.Fatal("expected: build deferred until first invocation")
+	}
+	for i := 0; i < 3; i++ {
+		if out, err := r.Invoke(i); err != nil || out != strconv.Itoa(i) {
+			t.Fatalf("expected: %d, got: %s, %v", i, out, err)
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("expected: 1 build, got: %d", builds)
+	}
+}
+
+func TestLazy_Error(t *testing.T) {
+	errDial := errors.New("dial")
+	r := chain.Lazy(func() (chain.Runnable[int, string], error) {
+		return nil, errDial
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := r.Invoke(i); !errors.Is(err, errDial) {
+			t.Fatalf("expected: %v, got: %v", errDial, err)
+		}
+	}
+}
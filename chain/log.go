@@ -0,0 +1,36 @@
+package chain
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Logged takes a Runnable instance and returns a new Runnable instance that logs the entry and exit of each stage
+// with the given level, including the duration of the stage. Failed stages are logged with slog.LevelError.
+// Stages are identified by name (see Named) or 0-based index. If logger is nil, slog.Default() is used.
+//
+// If r is a chain created by ChainN or a Pipe, every stage is logged; otherwise r is logged as a single stage.
+func Logged[T1, T2 any](r Runnable[T1, T2], logger *slog.Logger, level slog.Level) Runnable[T1, T2] {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	stages := stagesOf(r)
+	wrapped := make([]stage, len(stages))
+	for i, s := range stages {
+		name := s.label(i)
+		wrapped[i] = intercept(i, s, []Middleware{func(_ int, in any, next Invoker) (any, error) {
+			ctx := context.Background()
+			logger.Log(ctx, level, "chain stage started", "stage", name)
+			start := time.Now()
+			out, err := next(in)
+			if err != nil {
+				logger.Log(ctx, slog.LevelError, "chain stage failed", "stage", name, "duration", time.Since(start), "error", err)
+			} else {
+				logger.Log(ctx, level, "chain stage finished", "stage", name, "duration", time.Since(start))
+			}
+			return out, err
+		}})
+	}
+	return newPipe[T1, T2](wrapped)
+}
@@ -0,0 +1,41 @@
+package chain_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	r := chain.Logged(chain.Chain2(chain.Named("atoi", chain.Func2(strconv.Atoi)), chain.Func(func(i int) int {
+		return i * 2
+	})), logger, slog.LevelDebug)
+
+	if _, err := r.Invoke("1"); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"chain stage started\" stage=atoi",
+		"chain stage finished\" stage=atoi",
+		"chain stage finished\" stage=1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected: %q in log, got: %s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if _, err := r.Invoke("x"); err == nil {
+		t.Fatal("expected: error")
+	}
+	if out := buf.String(); !strings.Contains(out, "level=ERROR msg=\"chain stage failed\" stage=atoi") {
+		t.Fatalf("expected: error logged, got: %s", out)
+	}
+}
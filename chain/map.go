@@ -0,0 +1,90 @@
+package chain
+
+import "sync"
+
+type mapOptions struct {
+	concurrency int
+}
+
+// MapOption is a configuration option for the Map function.
+type MapOption func(*mapOptions)
+
+// WithConcurrency sets the maximum number of elements processed concurrently.
+// A value of 1 or less processes elements sequentially.
+func WithConcurrency(n int) MapOption {
+	return func(o *mapOptions) {
+		o.concurrency = n
+	}
+}
+
+func (o *mapOptions) apply(opts []MapOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+type mapper[T1, T2 any] struct {
+	r           Runnable[T1, T2]
+	concurrency int
+}
+
+func (m mapper[T1, T2]) Invoke(in []T1) (out []T2, err error) {
+	if in == nil {
+		return nil, nil
+	}
+	out = make([]T2, len(in))
+	if m.concurrency <= 1 || len(in) <= 1 {
+		for i, x := range in {
+			if out[i], err = m.r.Invoke(x); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		sem  = make(chan struct{}, m.concurrency)
+		done = make(chan struct{})
+	)
+loop:
+	for i, x := range in {
+		select {
+		case sem <- struct{}{}:
+		case <-done:
+			// Stop dispatching new elements after the first error.
+			break loop
+		}
+		wg.Add(1)
+		go func(i int, x T1) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			y, e := m.r.Invoke(x)
+			if e != nil {
+				once.Do(func() {
+					err = e
+					close(done)
+				})
+				return
+			}
+			out[i] = y
+		}(i, x)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Map takes a Runnable instance and returns a new Runnable instance that invokes it for each element of a slice.
+// The output slice has the same length and order as the input slice. If any invocation fails, the first error is returned.
+func Map[T1, T2 any](r Runnable[T1, T2], options ...MapOption) Runnable[[]T1, []T2] {
+	var o mapOptions
+	o.apply(options)
+	return mapper[T1, T2]{
+		r:           r,
+		concurrency: o.concurrency,
+	}
+}
@@ -0,0 +1,65 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestMap(t *testing.T) {
+	r := chain.Map(chain.Func(strconv.Itoa))
+	out, err := r.Invoke([]int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 3 || out[0] != "1" || out[1] != "2" || out[2] != "3" {
+		t.Fatalf("expected: [1 2 3], got: %v", out)
+	}
+}
+
+func TestMap_Concurrency(t *testing.T) {
+	var running, peak int32
+	r := chain.Map(chain.Func(func(i int) int {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		return i * i
+	}), chain.WithConcurrency(2))
+	in := make([]int, 100)
+	for i := range in {
+		in[i] = i
+	}
+	out, err := r.Invoke(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range out {
+		if v != i*i {
+			t.Fatalf("expected: %d, got: %d", i*i, v)
+		}
+	}
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Fatalf("expected: at most 2 concurrent invocations, got: %d", p)
+	}
+}
+
+func TestMap_Error(t *testing.T) {
+	errBad := errors.New("bad")
+	r := chain.Map(chain.Func2(func(i int) (int, error) {
+		if i == 3 {
+			return 0, errBad
+		}
+		return i, nil
+	}), chain.WithConcurrency(4))
+	if _, err := r.Invoke([]int{1, 2, 3, 4, 5}); !errors.Is(err, errBad) {
+		t.Fatalf("expected: %v, got: %v", errBad, err)
+	}
+}
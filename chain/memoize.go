@@ -0,0 +1,89 @@
+package chain
+
+import (
+	"container/list"
+	"sync"
+)
+
+type memoizeOptions struct {
+	maxEntries int
+}
+
+// MemoizeOption is a configuration option for the Memoize function.
+type MemoizeOption func(*memoizeOptions)
+
+// WithMaxEntries sets the maximum number of cached results. When the cache is full, the least recently used
+// result is evicted. A value of 0 or less means no limit (default).
+func WithMaxEntries(n int) MemoizeOption {
+	return func(o *memoizeOptions) {
+		o.maxEntries = n
+	}
+}
+
+func (o *memoizeOptions) apply(opts []MemoizeOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+type entry[T1 comparable, T2 any] struct {
+	key   T1
+	value T2
+}
+
+type memoizer[T1 comparable, T2 any] struct {
+	r          Runnable[T1, T2]
+	maxEntries int
+
+	mu    sync.Mutex
+	lru   *list.List
+	cache map[T1]*list.Element
+}
+
+func (m *memoizer[T1, T2]) Invoke(in T1) (out T2, err error) {
+	m.mu.Lock()
+	if e, ok := m.cache[in]; ok {
+		m.lru.MoveToFront(e)
+		out = e.Value.(*entry[T1, T2]).value
+		m.mu.Unlock()
+		return out, nil
+	}
+	m.mu.Unlock()
+
+	if out, err = m.r.Invoke(in); err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.cache[in]; ok {
+		// Another invocation cached the same input concurrently.
+		m.lru.MoveToFront(e)
+		e.Value.(*entry[T1, T2]).value = out
+		return
+	}
+	m.cache[in] = m.lru.PushFront(&entry[T1, T2]{key: in, value: out})
+	if m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		e := m.lru.Back()
+		m.lru.Remove(e)
+		delete(m.cache, e.Value.(*entry[T1, T2]).key)
+	}
+	return
+}
+
+// Memoize takes a Runnable instance and returns a new Runnable instance that caches the results of successful
+// invocations by input. Errors are not cached. The Runnable instance r should be pure: its output must only
+// depend on its input.
+//
+// The returned Runnable instance is safe for concurrent use. Concurrent invocations with the same uncached input
+// may invoke r more than once.
+func Memoize[T1 comparable, T2 any](r Runnable[T1, T2], options ...MemoizeOption) Runnable[T1, T2] {
+	var o memoizeOptions
+	o.apply(options)
+	return &memoizer[T1, T2]{
+		r:          r,
+		maxEntries: o.maxEntries,
+		lru:        list.New(),
+		cache:      make(map[T1]*list.Element),
+	}
+}
@@ -0,0 +1,44 @@
+package chain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := make(map[string]int)
+	r := chain.Memoize(chain.Func(func(s string) int {
+		calls[s]++
+		return len(s)
+	}), chain.WithMaxEntries(2))
+	for _, s := range []string{"a", "bb", "a", "bb"} {
+		if _, err := r.Invoke(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls["a"] != 1 || calls["bb"] != 1 {
+		t.Fatalf("expected: 1 call per input, got: %v", calls)
+	}
+	// "a" is the least recently used entry, so it is evicted by "ccc".
+	r.Invoke("ccc")
+	r.Invoke("bb")
+	r.Invoke("a")
+	if calls["a"] != 2 || calls["bb"] != 1 {
+		t.Fatalf("expected: a evicted and bb cached, got: %v", calls)
+	}
+}
+
+func TestMemoize_Error(t *testing.T) {
+	var calls int
+	r := chain.Memoize(chain.Func2(func(s string) (int, error) {
+		calls++
+		return 0, errors.New("bad")
+	}))
+	r.Invoke("a")
+	r.Invoke("a")
+	if calls != 2 {
+		t.Fatalf("expected: errors not cached, got: %d calls", calls)
+	}
+}
@@ -0,0 +1,130 @@
+package chain
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MetricsRecorder records the result of each stage invocation.
+type MetricsRecorder interface {
+	// Record records an invocation of the stage that took d and returned err.
+	Record(stage string, d time.Duration, err error)
+}
+
+// Instrument takes a Runnable instance and a recorder and returns a new Runnable instance that records the
+// latency and error of each stage invocation. Stages are identified by name (see Named) or 0-based index.
+// If r is a chain created by ChainN or a Pipe, every stage is recorded; otherwise r is recorded as a single stage.
+func Instrument[T1, T2 any](r Runnable[T1, T2], recorder MetricsRecorder) Runnable[T1, T2] {
+	if recorder == nil {
+		panic("nil recorder for Instrument")
+	}
+	stages := stagesOf(r)
+	wrapped := make([]stage, len(stages))
+	for i, s := range stages {
+		name := s.label(i)
+		wrapped[i] = intercept(i, s, []Middleware{func(_ int, in any, next Invoker) (any, error) {
+			start := time.Now()
+			out, err := next(in)
+			recorder.Record(name, time.Since(start), err)
+			return out, err
+		}})
+	}
+	return newPipe[T1, T2](wrapped)
+}
+
+// DefaultBuckets are the default upper bounds of latency histogram buckets used by Metrics.
+var DefaultBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// StageMetrics is a snapshot of the metrics of a stage.
+type StageMetrics struct {
+	// Count is the number of invocations.
+	Count int64 `json:"count"`
+	// Errors is the number of failed invocations.
+	Errors int64 `json:"errors"`
+	// Sum is the total latency of all invocations.
+	Sum time.Duration `json:"sum"`
+	// Buckets are the cumulative counts of invocations with latency less than or equal to
+	// the corresponding bucket bound, like a Prometheus histogram. The +Inf bucket is Count.
+	Buckets []int64 `json:"buckets"`
+}
+
+// Metrics is a MetricsRecorder that keeps invocation count, error count and a latency histogram per stage.
+// It implements expvar.Var, so it can be published with expvar.Publish.
+type Metrics struct {
+	bounds []time.Duration
+
+	mu     sync.Mutex
+	stages map[string]*StageMetrics
+}
+
+// NewMetrics creates a new Metrics with the given histogram bucket bounds, or DefaultBuckets if none are given.
+func NewMetrics(buckets ...time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	bounds := slices.Clone(buckets)
+	slices.Sort(bounds)
+	return &Metrics{
+		bounds: bounds,
+		stages: make(map[string]*StageMetrics),
+	}
+}
+
+// Record implements MetricsRecorder.
+func (m *Metrics) Record(stage string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.stages[stage]
+	if !ok {
+		s = &StageMetrics{Buckets: make([]int64, len(m.bounds))}
+		m.stages[stage] = s
+	}
+	s.Count++
+	if err != nil {
+		s.Errors++
+	}
+	s.Sum += d
+	for i, bound := range m.bounds {
+		if d <= bound {
+			s.Buckets[i]++
+		}
+	}
+}
+
+// Bounds returns the upper bounds of the histogram buckets.
+func (m *Metrics) Bounds() []time.Duration {
+	return slices.Clone(m.bounds)
+}
+
+// Snapshot returns a copy of the metrics of all stages.
+func (m *Metrics) Snapshot() map[string]StageMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]StageMetrics, len(m.stages))
+	for name, s := range m.stages {
+		c := *s
+		c.Buckets = slices.Clone(s.Buckets)
+		snapshot[name] = c
+	}
+	return snapshot
+}
+
+// String returns the JSON representation of the metrics. It implements expvar.Var.
+func (m *Metrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
@@ -0,0 +1,38 @@
+package chain_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestInstrument(t *testing.T) {
+	m := chain.NewMetrics(time.Hour)
+	r := chain.Instrument(chain.Chain2(chain.Named("atoi", chain.Func2(strconv.Atoi)), chain.Func(func(i int) int {
+		return i * 2
+	})), m)
+	r.Invoke("1")
+	r.Invoke("2")
+	if _, err := r.Invoke("x"); err == nil {
+		t.Fatal("expected: error")
+	}
+	snapshot := m.Snapshot()
+	atoi, double := snapshot["atoi"], snapshot["1"]
+	if atoi.Count != 3 || atoi.Errors != 1 || atoi.Buckets[0] != 3 {
+		t.Fatalf("unexpected metrics for atoi: %+v", atoi)
+	}
+	if double.Count != 2 || double.Errors != 0 {
+		t.Fatalf("unexpected metrics for stage 1: %+v", double)
+	}
+
+	var decoded map[string]chain.StageMetrics
+	if err := json.Unmarshal([]byte(m.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["atoi"].Count != 3 {
+		t.Fatalf("expected: 3 invocations in JSON, got: %s", m)
+	}
+}
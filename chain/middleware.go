@@ -0,0 +1,31 @@
+package chain
+
+// Invoker is a type-erased function that invokes a stage with the given input.
+type Invoker func(in any) (any, error)
+
+// Middleware intercepts the invocation of a stage. The stage is the 0-based index of the stage in the chain.
+// A middleware may call next to continue, skip it to short-circuit, or transform the returned output and error.
+type Middleware func(stage int, in any, next Invoker) (out any, err error)
+
+// intercept returns a stage that invokes s through the middlewares. The first middleware is the outermost.
+func intercept(index int, s stage, mws []Middleware) stage {
+	next := Invoker(s.invoke)
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, inner := mws[i], next
+		next = func(in any) (any, error) {
+			return mw(index, in, inner)
+		}
+	}
+	return s.withInvoke(next)
+}
+
+// Wrap takes a Runnable instance and returns a new Runnable instance that calls the middlewares around each of its stages.
+// If r is a chain created by ChainN or a Pipe, every stage is wrapped; otherwise r is treated as a single stage.
+func Wrap[T1, T2 any](r Runnable[T1, T2], mws ...Middleware) Runnable[T1, T2] {
+	stages := stagesOf(r)
+	wrapped := make([]stage, len(stages))
+	for i, s := range stages {
+		wrapped[i] = intercept(i, s, mws)
+	}
+	return newPipe[T1, T2](wrapped)
+}
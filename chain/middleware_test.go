@@ -0,0 +1,97 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestWrap(t *testing.T) {
+	var trace []string
+	mw := func(stage int, in any, next chain.Invoker) (any, error) {
+		trace = append(trace, "before "+strconv.Itoa(stage))
+		out, err := next(in)
+		trace = append(trace, "after "+strconv.Itoa(stage))
+		return out, err
+	}
+	r := chain.Wrap(chain.Chain2(chain.Func(func(s string) int {
+		return len(s)
+	}), chain.Func(strconv.Itoa)), mw)
+	out, err := r.Invoke("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "5" {
+		t.Fatalf("expected: 5, got: %s", out)
+	}
+	want := []string{"before 0", "after 0", "before 1", "after 1"}
+	if len(trace) != len(want) {
+		t.Fatalf("expected: %v, got: %v", want, trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("expected: %v, got: %v", want, trace)
+		}
+	}
+}
+
+func TestWrap_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+	var called bool
+	deny := func(stage int, in any, next chain.Invoker) (any, error) {
+		if stage == 1 {
+			return nil, errDenied
+		}
+		return next(in)
+	}
+	r := chain.Wrap(chain.Then(chain.New(chain.Func(func(s string) int {
+		return len(s)
+	})), chain.Func(func(i int) string {
+		called = true
+		return strconv.Itoa(i)
+	})), deny)
+	if _, err := r.Invoke("hello"); !errors.Is(err, errDenied) {
+		t.Fatalf("expected: %v, got: %v", errDenied, err)
+	}
+	if called {
+		t.Fatal("expected: short-circuited stage not called")
+	}
+}
+
+func TestWrap_TypeMismatch(t *testing.T) {
+	r := chain.Chain2(chain.Func(func(s string) int {
+		return len(s)
+	}), chain.Func(strconv.Itoa))
+
+	output := chain.Wrap(r, func(stage int, in any, next chain.Invoker) (any, error) {
+		if stage == 0 {
+			return "five", nil
+		}
+		return next(in)
+	})
+	if _, err := output.Invoke("hello"); !errors.Is(err, chain.ErrTypeMismatch) {
+		t.Fatalf("expected: %v, got: %v", chain.ErrTypeMismatch, err)
+	}
+
+	input := chain.Wrap(r, func(stage int, in any, next chain.Invoker) (any, error) {
+		if stage == 1 {
+			return next(int64(5))
+		}
+		return next(in)
+	})
+	if _, err := input.Invoke("hello"); !errors.Is(err, chain.ErrTypeMismatch) {
+		t.Fatalf("expected: %v, got: %v", chain.ErrTypeMismatch, err)
+	}
+
+	zero := chain.Wrap(r, func(stage int, in any, next chain.Invoker) (any, error) {
+		if stage == 0 {
+			return nil, nil
+		}
+		return next(in)
+	})
+	if out, err := zero.Invoke("hello"); err != nil || out != "0" {
+		t.Fatalf("expected: 0, got: %s, %v", out, err)
+	}
+}
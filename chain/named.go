@@ -0,0 +1,44 @@
+package chain
+
+import "fmt"
+
+// namer is implemented by Runnable instances that have a name.
+type namer interface {
+	Name() string
+}
+
+// nameOf returns the name of the Runnable instance r, or an empty string if r is not named.
+func nameOf(r any) string {
+	if n, ok := r.(namer); ok {
+		return n.Name()
+	}
+	return ""
+}
+
+// stageError wraps the error returned by a stage with the name of the stage, or its index if the stage is not named.
+func stageError(name string, index int, err error) error {
+	if name != "" {
+		return fmt.Errorf("stage %q: %w", name, err)
+	}
+	return fmt.Errorf("stage %d: %w", index, err)
+}
+
+type named[T1, T2 any] struct {
+	name string
+	r    Runnable[T1, T2]
+}
+
+// Name returns the name of the stage.
+func (n named[T1, T2]) Name() string {
+	return n.name
+}
+
+func (n named[T1, T2]) Invoke(in T1) (out T2, err error) {
+	return n.r.Invoke(in)
+}
+
+// Named takes a name and a Runnable instance and returns a new Runnable instance with the given name.
+// When a named stage of a chain created by ChainN or a Pipe fails, the error is wrapped with the name of the stage.
+func Named[T1, T2 any](name string, r Runnable[T1, T2]) Runnable[T1, T2] {
+	return named[T1, T2]{name: name, r: r}
+}
@@ -0,0 +1,38 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestNamed(t *testing.T) {
+	errBad := errors.New("bad")
+	fail := chain.Func2(func(i int) (string, error) {
+		return "", errBad
+	})
+	length := chain.Func(func(s string) int {
+		return len(s)
+	})
+
+	r := chain.Chain2(length, chain.Named("format", fail))
+	_, err := r.Invoke("hello")
+	if !errors.Is(err, errBad) {
+		t.Fatalf("expected: %v, got: %v", errBad, err)
+	}
+	if want := `stage "format": bad`; err.Error() != want {
+		t.Fatalf("expected: %s, got: %s", want, err)
+	}
+
+	p := chain.Then(chain.New(length), fail)
+	if _, err := p.Invoke("hello"); err == nil || err.Error() != "stage 1: bad" {
+		t.Fatalf("expected: stage 1: bad, got: %v", err)
+	}
+
+	out, err := chain.Named("itoa", chain.Func(strconv.Itoa)).Invoke(1)
+	if err != nil || out != "1" {
+		t.Fatalf("expected: 1, got: %s, %v", out, err)
+	}
+}
@@ -0,0 +1,67 @@
+package chain
+
+// Option represents an optional value: either a value (Some) or no value (None).
+type Option[T any] struct {
+	value T
+	ok    bool
+}
+
+// Some returns an Option with the value v.
+func Some[T any](v T) Option[T] {
+	return Option[T]{value: v, ok: true}
+}
+
+// None returns an Option with no value.
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// Get returns the value and whether the Option has a value.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+// IsSome reports whether the Option has a value.
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+type skipZero[T comparable] struct {
+	r Runnable[T, T]
+}
+
+func (s skipZero[T]) Invoke(in T) (out T, err error) {
+	var zero T
+	if in == zero {
+		return in, nil
+	}
+	return s.r.Invoke(in)
+}
+
+// SkipZero takes a Runnable instance and returns a new Runnable instance that passes the zero value through
+// unchanged and only invokes r for non-zero inputs.
+func SkipZero[T comparable](r Runnable[T, T]) Runnable[T, T] {
+	return skipZero[T]{r: r}
+}
+
+type skipNone[T1, T2 any] struct {
+	r Runnable[T1, T2]
+}
+
+func (s skipNone[T1, T2]) Invoke(in Option[T1]) (out Option[T2], err error) {
+	v, ok := in.Get()
+	if !ok {
+		return None[T2](), nil
+	}
+	y, err := s.r.Invoke(v)
+	if err != nil {
+		return
+	}
+	return Some(y), nil
+}
+
+// SkipNone takes a Runnable instance and returns a new Runnable instance over Options that propagates None
+// unchanged and only invokes r for Options with a value.
+func SkipNone[T1, T2 any](r Runnable[T1, T2]) Runnable[Option[T1], Option[T2]] {
+	return skipNone[T1, T2]{r: r}
+}
@@ -0,0 +1,44 @@
+package chain_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestSkipZero(t *testing.T) {
+	var calls int
+	r := chain.SkipZero(chain.Func(func(s string) string {
+		calls++
+		return strings.ToUpper(s)
+	}))
+	if out, _ := r.Invoke(""); out != "" {
+		t.Fatalf("expected: empty string, got: %s", out)
+	}
+	if out, _ := r.Invoke("a"); out != "A" {
+		t.Fatalf("expected: A, got: %s", out)
+	}
+	if calls != 1 {
+		t.Fatalf("expected: 1 call, got: %d", calls)
+	}
+}
+
+func TestSkipNone(t *testing.T) {
+	r := chain.SkipNone(chain.Func(strconv.Itoa))
+	out, err := r.Invoke(chain.Some(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := out.Get(); !ok || v != "1" {
+		t.Fatalf("expected: Some(1), got: %v, %v", v, ok)
+	}
+	out, err = r.Invoke(chain.None[int]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.IsSome() {
+		t.Fatal("expected: None")
+	}
+}
@@ -0,0 +1,49 @@
+package chain
+
+import "sync"
+
+// keyLock is a mutex shared by the invocations with the same key.
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+type perKey[K comparable, T1, T2 any] struct {
+	key func(T1) K
+	r   Runnable[T1, T2]
+
+	mu    sync.Mutex
+	locks map[K]*keyLock
+}
+
+func (p *perKey[K, T1, T2]) Invoke(in T1) (out T2, err error) {
+	k := p.key(in)
+	p.mu.Lock()
+	l, ok := p.locks[k]
+	if !ok {
+		l = &keyLock{}
+		p.locks[k] = l
+	}
+	l.refs++
+	p.mu.Unlock()
+
+	l.Lock()
+	defer func() {
+		l.Unlock()
+		p.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(p.locks, k)
+		}
+		p.mu.Unlock()
+	}()
+	return p.r.Invoke(in)
+}
+
+// PerKey takes a key function and a Runnable instance and returns a new Runnable instance that serializes
+// invocations with the same key, while invocations with different keys run concurrently.
+func PerKey[K comparable, T1, T2 any](key func(T1) K, r Runnable[T1, T2]) Runnable[T1, T2] {
+	if key == nil {
+		panic("nil key function for PerKey")
+	}
+	return &perKey[K, T1, T2]{key: key, r: r, locks: make(map[K]*keyLock)}
+}
@@ -0,0 +1,46 @@
+package chain_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestPerKey(t *testing.T) {
+	var running [2]int32
+	var overlapped, concurrent int32
+	var total int32
+	r := chain.PerKey(func(i int) int {
+		return i % 2
+	}, chain.Func(func(i int) int {
+		k := i % 2
+		if atomic.AddInt32(&running[k], 1) > 1 {
+			atomic.StoreInt32(&overlapped, 1)
+		}
+		if atomic.AddInt32(&total, 1) > 1 {
+			atomic.StoreInt32(&concurrent, 1)
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&total, -1)
+		atomic.AddInt32(&running[k], -1)
+		return i
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Invoke(i)
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&overlapped) != 0 {
+		t.Fatal("expected: invocations with the same key serialized")
+	}
+	if atomic.LoadInt32(&concurrent) == 0 {
+		t.Fatal("expected: invocations with different keys run concurrently")
+	}
+}
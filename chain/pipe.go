@@ -0,0 +1,110 @@
+package chain
+
+import "sync"
+
+// Pipe is a builder that composes an arbitrary number of Runnable instances at runtime.
+// A Pipe is immutable: each call to Then returns a new Pipe and leaves the original unchanged.
+//
+// Usage:
+//
+//	p := chain.New(r1)
+//	p2 := chain.Then(p, r2)
+//	r := chain.Then(p2, r3).Build()
+//
+// Go methods cannot declare type parameters, so the Then function is used for stages that change
+// the output type, and the Then method for stages that keep it:
+//
+//	r := chain.Start(trim).Then(lower).Then(normalize).Build()
+//
+// The type of each added Runnable is still checked against the output type of the Pipe.
+//
+// Composed Runnable instances (Pipes and chains created by ChainN) are flattened into the stages of
+// the Pipe, so building a Pipe from other chains does not nest them at runtime.
+type Pipe[T1, T2 any] struct {
+	stages []stage
+	frames sync.Pool
+}
+
+// frame holds the cells of one invocation of a Pipe: the input cell followed by the output cell of each stage.
+type frame struct {
+	cells []any
+}
+
+func newPipe[T1, T2 any](stages []stage) *Pipe[T1, T2] {
+	p := &Pipe[T1, T2]{stages: stages}
+	p.frames.New = func() any {
+		cells := make([]any, len(stages)+1)
+		cells[0] = stages[0].newIn()
+		for i, s := range stages {
+			cells[i+1] = s.newOut()
+		}
+		return &frame{cells: cells}
+	}
+	return p
+}
+
+// New returns a new Pipe that starts with the given Runnable instance.
+func New[T1, T2 any](r Runnable[T1, T2]) *Pipe[T1, T2] {
+	return newPipe[T1, T2](stagesOf(r))
+}
+
+// Start is like New. It reads better at the beginning of a fluent chain of Then calls.
+func Start[T1, T2 any](r Runnable[T1, T2]) *Pipe[T1, T2] {
+	return New(r)
+}
+
+// Then returns a new Pipe that appends the Runnable instance r to the Pipe p.
+func Then[T1, T2, T3 any](p *Pipe[T1, T2], r Runnable[T2, T3]) *Pipe[T1, T3] {
+	return newPipe[T1, T3](appendStages(p.stages, stagesOf(r)))
+}
+
+// Then returns a new Pipe that appends the Runnable instance r, which keeps the output type, to the Pipe.
+func (p *Pipe[T1, T2]) Then(r Runnable[T2, T2]) *Pipe[T1, T2] {
+	return newPipe[T1, T2](appendStages(p.stages, stagesOf(r)))
+}
+
+// All takes any number of Runnable instances of the same input and output type and returns a new Runnable
+// instance that chains them together. If no Runnable instance is given, the input is returned unchanged.
+func All[T any](rs ...Runnable[T, T]) Runnable[T, T] {
+	if len(rs) == 0 {
+		return Identity[T]()
+	}
+	stages := make([][]stage, len(rs))
+	for i, r := range rs {
+		stages[i] = stagesOf(r)
+	}
+	return newPipe[T, T](appendStages(stages...))
+}
+
+// Len returns the number of stages in the Pipe.
+func (p *Pipe[T1, T2]) Len() int {
+	return len(p.stages)
+}
+
+// Invoke runs all stages of the Pipe in order in a single loop, stopping at the first error.
+func (p *Pipe[T1, T2]) Invoke(in T1) (out T2, err error) {
+	f := p.frames.Get().(*frame)
+	f.cells[0].(*cell[T1]).v = in
+	for i, s := range p.stages {
+		if err = s.run(f.cells[i], f.cells[i+1]); err != nil {
+			err = stageError(s.name, i, err)
+			break
+		}
+	}
+	if err == nil {
+		c := f.cells[len(f.cells)-1].(*cell[T2])
+		out = c.v
+		c.v = *new(T2)
+	}
+	p.frames.Put(f)
+	return
+}
+
+// Build returns a Runnable instance that runs all stages of the Pipe.
+func (p *Pipe[T1, T2]) Build() Runnable[T1, T2] {
+	return p
+}
+
+func (p *Pipe[T1, T2]) flatten() []stage {
+	return p.stages
+}
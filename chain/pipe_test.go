@@ -0,0 +1,100 @@
+package chain_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestPipe(t *testing.T) {
+	// Create a Pipe that starts with a Runnable instance that takes a string and returns an int.
+	p := chain.New(chain.Func(func(s string) int {
+		return len(s)
+	}))
+	// Append a Runnable instance that takes an int and returns a string.
+	p2 := chain.Then(p, chain.Func(func(i int) string {
+		return strconv.Itoa(i)
+	}))
+	// Append a Runnable instance that takes a string and returns an int.
+	p3 := chain.Then(p2, chain.Func2(func(s string) (int, error) {
+		return strconv.Atoi(s)
+	}))
+	if p3.Len() != 3 {
+		t.Fatalf("expected: 3 stages, got: %d", p3.Len())
+	}
+	out, err := p3.Build().Invoke("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 5 {
+		t.Fatalf("expected: 5, got: %d", out)
+	}
+	// The original Pipe must not be modified by Then.
+	if p.Len() != 1 {
+		t.Fatalf("expected: 1 stage, got: %d", p.Len())
+	}
+}
+
+func TestPipe_Error(t *testing.T) {
+	errStop := errors.New("stop")
+	var called bool
+	p := chain.Then(chain.New(chain.Func2(func(s string) (int, error) {
+		return 0, errStop
+	})), chain.Func(func(i int) int {
+		called = true
+		return i
+	}))
+	if _, err := p.Invoke("hello"); !errors.Is(err, errStop) {
+		t.Fatalf("expected: %v, got: %v", errStop, err)
+	}
+	if called {
+		t.Fatal("expected: stage after error not called")
+	}
+}
+
+func TestPipe_Fluent(t *testing.T) {
+	trim := chain.Func(strings.TrimSpace)
+	lower := chain.Func(strings.ToLower)
+	dedup := chain.Func(func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	})
+	// Chains passed to Start and Then are flattened into the stages of the Pipe.
+	p := chain.Start(chain.Chain2(trim, lower)).Then(dedup).Then(chain.Named("exclaim", chain.Func(func(s string) string {
+		return s + "!"
+	})))
+	if p.Len() != 4 {
+		t.Fatalf("expected: 4 stages, got: %d", p.Len())
+	}
+	out, err := chain.Then(p, chain.Func(func(s string) int {
+		return len(s)
+	})).Build().Invoke("  Hello   World ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != len("hello world!") {
+		t.Fatalf("expected: %d, got: %d", len("hello world!"), out)
+	}
+}
+
+func TestAll(t *testing.T) {
+	r := chain.All(
+		chain.Func(strings.TrimSpace),
+		chain.Func(strings.ToLower),
+		chain.All(chain.Func(func(s string) string {
+			return strings.ReplaceAll(s, " ", "-")
+		})),
+	)
+	out, err := r.Invoke("  Hello World ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "hello-world" {
+		t.Fatalf("expected: hello-world, got: %s", out)
+	}
+	if out, _ := chain.All[string]().Invoke("x"); out != "x" {
+		t.Fatalf("expected: x, got: %s", out)
+	}
+}
@@ -0,0 +1,204 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+// PipelineStage is a stage of a concurrent pipeline created by RunPipeline.
+type PipelineStage struct {
+	stages  []stage
+	workers int
+	buffer  int
+}
+
+// StageOption is a configuration option for the Stage function.
+type StageOption func(*PipelineStage)
+
+// WithWorkers sets the number of goroutines that run the stage (default is 1).
+func WithWorkers(n int) StageOption {
+	if n <= 0 {
+		panic("non-positive workers for WithWorkers")
+	}
+	return func(s *PipelineStage) {
+		s.workers = n
+	}
+}
+
+// WithBuffer sets the buffer size of the output channel of the stage (default is 0).
+func WithBuffer(n int) StageOption {
+	if n < 0 {
+		panic("negative buffer for WithBuffer")
+	}
+	return func(s *PipelineStage) {
+		s.buffer = n
+	}
+}
+
+// Stage returns a PipelineStage that runs the Runnable instance r. If r is a chain or a Pipe,
+// all of its stages run sequentially in the goroutines of the PipelineStage.
+func Stage[T1, T2 any](r Runnable[T1, T2], options ...StageOption) PipelineStage {
+	s := PipelineStage{stages: stagesOf(r), workers: 1}
+	for _, opt := range options {
+		opt(&s)
+	}
+	return s
+}
+
+func (s PipelineStage) in() reflect.Type {
+	return s.stages[0].in
+}
+
+func (s PipelineStage) out() reflect.Type {
+	return s.stages[len(s.stages)-1].out
+}
+
+func (s PipelineStage) invoke(x any) (any, error) {
+	for i, st := range s.stages {
+		var err error
+		if x, err = st.invoke(x); err != nil {
+			return nil, stageError(st.name, i, err)
+		}
+	}
+	return x, nil
+}
+
+type pipelineOptions struct {
+	onError func(ctx context.Context, stage int, in any, err error)
+}
+
+// PipelineOption is a configuration option for the RunPipeline function.
+type PipelineOption func(*pipelineOptions)
+
+// WithStageErrorHandler sets the function called with the 0-based index of the stage, the input and the error
+// when a stage fails. Failed inputs are dropped; by default they are dropped silently.
+func WithStageErrorHandler(f func(ctx context.Context, stage int, in any, err error)) PipelineOption {
+	return func(o *pipelineOptions) {
+		o.onError = f
+	}
+}
+
+// RunPipeline starts a concurrent pipeline that reads inputs from in, passes them through the stages,
+// and writes the outputs to out. Each stage runs in its own goroutines and is connected to the next stage
+// by a channel. The order of the outputs is not preserved if any stage has more than one worker.
+//
+// An error is returned if a stage is not created by Stage, or if the input and output types of adjacent
+// stages do not match.
+//
+// The pipeline completes when in is closed and all stages are drained, or when the context is canceled.
+// The out channel is closed when the pipeline completes, so RunPipeline must be the only writer of out.
+func RunPipeline[T1, T2 any](ctx context.Context, in <-chan T1, out chan<- T2, stages []PipelineStage, options ...PipelineOption) (spawn.Handle, error) {
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("no stages")
+	}
+	for i, s := range stages {
+		if len(s.stages) == 0 {
+			return nil, fmt.Errorf("stage %d: PipelineStage not created by Stage", i)
+		}
+	}
+	if t := reflect.TypeFor[T1](); stages[0].in() != t {
+		return nil, fmt.Errorf("stage 0: input type %v does not match %v", stages[0].in(), t)
+	}
+	for i := 1; i < len(stages); i++ {
+		if stages[i].in() != stages[i-1].out() {
+			return nil, fmt.Errorf("stage %d: input type %v does not match output type %v of stage %d", i, stages[i].in(), stages[i-1].out(), i-1)
+		}
+	}
+	if t := reflect.TypeFor[T2](); stages[len(stages)-1].out() != t {
+		return nil, fmt.Errorf("stage %d: output type %v does not match %v", len(stages)-1, stages[len(stages)-1].out(), t)
+	}
+	var o pipelineOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	return spawn.Run(ctx, func(ctx context.Context) {
+		defer close(out)
+		var wg sync.WaitGroup
+		run := func(f func()) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f()
+			}()
+		}
+
+		// Convert the typed input channel into the channel of the first stage.
+		src := make(chan any)
+		next := src
+		run(func() {
+			defer close(src)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case x, ok := <-in:
+					if !ok {
+						return
+					}
+					if !send(ctx, src, any(x)) {
+						return
+					}
+				}
+			}
+		})
+
+		for i, s := range stages {
+			src, dst := next, make(chan any, s.buffer)
+			var workers sync.WaitGroup
+			for j := 0; j < s.workers; j++ {
+				workers.Add(1)
+				run(func() {
+					defer workers.Done()
+					for x := range src {
+						y, err := s.invoke(x)
+						if err != nil {
+							if o.onError != nil {
+								o.onError(ctx, i, x, err)
+							}
+							continue
+						}
+						if !send(ctx, dst, y) {
+							return
+						}
+					}
+				})
+			}
+			run(func() {
+				workers.Wait()
+				close(dst)
+			})
+			next = dst
+		}
+
+		// Convert the channel of the last stage into the typed output channel.
+		for x := range next {
+			y, err := unbox[T2](x)
+			if err != nil {
+				if o.onError != nil {
+					o.onError(ctx, len(stages)-1, x, err)
+				}
+				continue
+			}
+			select {
+			case out <- y:
+			case <-ctx.Done():
+			}
+		}
+		wg.Wait()
+	}), nil
+}
+
+// send sends x to ch unless the context is done first, and reports whether x was sent.
+func send(ctx context.Context, ch chan<- any, x any) bool {
+	select {
+	case ch <- x:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package chain_test
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestRunPipeline(t *testing.T) {
+	in := make(chan string)
+	out := make(chan string)
+	var failed int32
+	h, err := chain.RunPipeline(context.Background(), in, out, []chain.PipelineStage{
+		chain.Stage(chain.Func2(strconv.Atoi), chain.WithWorkers(3), chain.WithBuffer(4)),
+		chain.Stage(chain.Chain2(chain.Func(func(i int) int {
+			return i * i
+		}), chain.Func(strconv.Itoa)), chain.WithWorkers(2)),
+	}, chain.WithStageErrorHandler(func(ctx context.Context, stage int, in any, err error) {
+		if stage != 0 || in != "x" {
+			t.Errorf("unexpected error at stage %d for %v: %v", stage, in, err)
+		}
+		atomic.AddInt32(&failed, 1)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer close(in)
+		for _, s := range []string{"1", "2", "x", "3"} {
+			in <- s
+		}
+	}()
+	var got []string
+	for s := range out {
+		got = append(got, s)
+	}
+	h.Join(context.Background())
+	sort.Strings(got)
+	if len(got) != 3 || got[0] != "1" || got[1] != "4" || got[2] != "9" {
+		t.Fatalf("expected: [1 4 9], got: %v", got)
+	}
+	if atomic.LoadInt32(&failed) != 1 {
+		t.Fatalf("expected: 1 failure, got: %d", failed)
+	}
+}
+
+func TestRunPipeline_TypeMismatch(t *testing.T) {
+	_, err := chain.RunPipeline(context.Background(), make(chan string), make(chan string), []chain.PipelineStage{
+		chain.Stage(chain.Func2(strconv.Atoi)),
+		chain.Stage(chain.Func(func(s string) string { return s })),
+	})
+	if err == nil {
+		t.Fatal("expected: type mismatch error")
+	}
+}
+
+func TestRunPipeline_ZeroStage(t *testing.T) {
+	_, err := chain.RunPipeline(context.Background(), make(chan int), make(chan int), []chain.PipelineStage{
+		chain.Stage(chain.Identity[int]()),
+		{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "stage 1") {
+		t.Fatalf("expected: error for stage 1, got: %v", err)
+	}
+}
+
+func TestRunPipeline_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := make(chan int)
+	h, err := chain.RunPipeline(ctx, in, out, []chain.PipelineStage{
+		chain.Stage(chain.Identity[int](), chain.WithWorkers(2)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in <- 1
+	cancel()
+	h.Join(context.Background())
+	for range out {
+	}
+}
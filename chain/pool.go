@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPoolFull is the error returned by a Pool created with WithReject when all slots are in use.
+var ErrPoolFull = errors.New("pool is full")
+
+type poolOptions struct {
+	reject bool
+}
+
+// PoolOption is a configuration option for the Pool functions.
+type PoolOption func(*poolOptions)
+
+// WithReject specifies whether to reject invocations with ErrPoolFull instead of queuing them when all slots are in use.
+func WithReject(reject bool) PoolOption {
+	return func(o *poolOptions) {
+		o.reject = reject
+	}
+}
+
+func (o *poolOptions) apply(opts []PoolOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+type pool[T1, T2 any] struct {
+	r      Runnable[T1, T2]
+	slots  chan struct{}
+	reject bool
+}
+
+func (p pool[T1, T2]) Invoke(in T1) (out T2, err error) {
+	if p.reject {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			return out, ErrPoolFull
+		}
+	} else {
+		p.slots <- struct{}{}
+	}
+	defer func() { <-p.slots }()
+	return p.r.Invoke(in)
+}
+
+// Pool takes a Runnable instance and returns a new Runnable instance that allows at most size concurrent invocations.
+// Invocations beyond that wait for a free slot, or fail with ErrPoolFull if WithReject is set.
+func Pool[T1, T2 any](r Runnable[T1, T2], size int, options ...PoolOption) Runnable[T1, T2] {
+	if size <= 0 {
+		panic("non-positive size for Pool")
+	}
+	var o poolOptions
+	o.apply(options)
+	return pool[T1, T2]{r: r, slots: make(chan struct{}, size), reject: o.reject}
+}
+
+type poolCtx[T1, T2 any] struct {
+	r      RunnableContext[T1, T2]
+	slots  chan struct{}
+	reject bool
+}
+
+func (p poolCtx[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	if p.reject {
+		select {
+		case p.slots <- struct{}{}:
+		default:
+			return out, ErrPoolFull
+		}
+	} else {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return out, ctx.Err()
+		}
+	}
+	defer func() { <-p.slots }()
+	return p.r.Invoke(ctx, in)
+}
+
+// PoolCtx is like Pool but for RunnableContext instances. Waiting for a free slot stops when the context is done.
+func PoolCtx[T1, T2 any](r RunnableContext[T1, T2], size int, options ...PoolOption) RunnableContext[T1, T2] {
+	if size <= 0 {
+		panic("non-positive size for PoolCtx")
+	}
+	var o poolOptions
+	o.apply(options)
+	return poolCtx[T1, T2]{r: r, slots: make(chan struct{}, size), reject: o.reject}
+}
@@ -0,0 +1,74 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestPool(t *testing.T) {
+	var running, peak int32
+	r := chain.Pool(chain.Func(func(i int) int {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return i
+	}), 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Invoke(i)
+		}()
+	}
+	wg.Wait()
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Fatalf("expected: at most 2 concurrent invocations, got: %d", p)
+	}
+}
+
+func TestPool_Reject(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	r := chain.Pool(chain.Func(func(i int) int {
+		close(started)
+		<-release
+		return i
+	}), 1, chain.WithReject(true))
+	go r.Invoke(1)
+	<-started
+	if _, err := r.Invoke(2); !errors.Is(err, chain.ErrPoolFull) {
+		t.Fatalf("expected: %v, got: %v", chain.ErrPoolFull, err)
+	}
+	close(release)
+}
+
+func TestPoolCtx_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	defer close(release)
+	r := chain.PoolCtx(chain.FuncCtx(func(ctx context.Context, i int) int {
+		close(started)
+		<-release
+		return i
+	}), 1)
+	go r.Invoke(context.Background(), 1)
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := r.Invoke(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,50 @@
+package chain
+
+import "iter"
+
+type reducer[T, Acc any] struct {
+	init Acc
+	f    func(Acc, T) (Acc, error)
+}
+
+func (r reducer[T, Acc]) Invoke(in []T) (out Acc, err error) {
+	out = r.init
+	for _, x := range in {
+		if out, err = r.f(out, x); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Reduce takes an initial value and an accumulator function and returns a Runnable instance that folds
+// the elements of a slice into a single value. It stops at the first error returned by f.
+func Reduce[T, Acc any](init Acc, f func(Acc, T) (Acc, error)) Runnable[[]T, Acc] {
+	if f == nil {
+		panic("nil function for Reduce")
+	}
+	return reducer[T, Acc]{init: init, f: f}
+}
+
+type seqReducer[T, Acc any] struct {
+	init Acc
+	f    func(Acc, T) (Acc, error)
+}
+
+func (r seqReducer[T, Acc]) Invoke(in iter.Seq[T]) (out Acc, err error) {
+	out = r.init
+	for x := range in {
+		if out, err = r.f(out, x); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ReduceSeq is like Reduce but folds the elements of a sequence. It stops consuming the sequence at the first error.
+func ReduceSeq[T, Acc any](init Acc, f func(Acc, T) (Acc, error)) Runnable[iter.Seq[T], Acc] {
+	if f == nil {
+		panic("nil function for ReduceSeq")
+	}
+	return seqReducer[T, Acc]{init: init, f: f}
+}
@@ -0,0 +1,45 @@
+package chain_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func sum(acc, x int) (int, error) {
+	return acc + x, nil
+}
+
+func TestReduce(t *testing.T) {
+	out, err := chain.Reduce(10, sum).Invoke([]int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 16 {
+		t.Fatalf("expected: 16, got: %d", out)
+	}
+
+	errNegative := errors.New("negative")
+	r := chain.Reduce(0, func(acc, x int) (int, error) {
+		if x < 0 {
+			return acc, errNegative
+		}
+		return acc + x, nil
+	})
+	if _, err := r.Invoke([]int{1, -1, 2}); !errors.Is(err, errNegative) {
+		t.Fatalf("expected: %v, got: %v", errNegative, err)
+	}
+}
+
+func TestReduceSeq(t *testing.T) {
+	r := chain.Chain3(chain.Identity[[]int](), chain.Values[int](), chain.ReduceSeq(0, sum))
+	out, err := r.Invoke(slices.Repeat([]int{1}, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 5 {
+		t.Fatalf("expected: 5, got: %d", out)
+	}
+}
@@ -0,0 +1,143 @@
+package chain
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type retryOptions struct {
+	maxAttempts int
+	initial     time.Duration
+	max         time.Duration
+	multiplier  float64
+	jitter      float64
+	retryable   func(error) bool
+}
+
+// RetryOption is a configuration option for the Retry functions.
+type RetryOption func(*retryOptions)
+
+// WithMaxAttempts sets the maximum number of attempts, including the first one (default is 3).
+func WithMaxAttempts(n int) RetryOption {
+	if n <= 0 {
+		panic("non-positive attempts for WithMaxAttempts")
+	}
+	return func(o *retryOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the initial and maximum delay between attempts (default is 100ms and 10s).
+// The delay is multiplied by the multiplier after each failed attempt (default is 2).
+func WithBackoff(initial, max time.Duration, multiplier float64) RetryOption {
+	if initial < 0 || max < initial {
+		panic("invalid delay for WithBackoff")
+	}
+	if multiplier < 1 {
+		panic("multiplier less than 1 for WithBackoff")
+	}
+	return func(o *retryOptions) {
+		o.initial = initial
+		o.max = max
+		o.multiplier = multiplier
+	}
+}
+
+// WithJitter sets the fraction of the delay that is randomized, in the range [0, 1] (default is 0.2).
+// For example, a jitter of 0.2 turns a delay of 100ms into a random delay between 80ms and 120ms.
+func WithJitter(jitter float64) RetryOption {
+	if jitter < 0 || jitter > 1 {
+		panic("jitter out of range for WithJitter")
+	}
+	return func(o *retryOptions) {
+		o.jitter = jitter
+	}
+}
+
+// WithRetryIf sets the predicate that decides whether an error is retryable (default is all errors).
+func WithRetryIf(f func(error) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.retryable = f
+	}
+}
+
+func (o *retryOptions) apply(opts []RetryOption) {
+	o.maxAttempts = 3
+	o.initial = 100 * time.Millisecond
+	o.max = 10 * time.Second
+	o.multiplier = 2
+	o.jitter = 0.2
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// delay returns the delay before the given attempt (1-based, the first retry is attempt 1).
+func (o *retryOptions) delay(attempt int) time.Duration {
+	d := float64(o.initial)
+	for i := 1; i < attempt && d < float64(o.max); i++ {
+		d *= o.multiplier
+	}
+	if d > float64(o.max) {
+		d = float64(o.max)
+	}
+	if o.jitter > 0 {
+		d += d * o.jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// shouldRetry reports whether another attempt should be made after the given attempt failed with err.
+func (o *retryOptions) shouldRetry(attempt int, err error) bool {
+	return attempt < o.maxAttempts && (o.retryable == nil || o.retryable(err))
+}
+
+type retry[T1, T2 any] struct {
+	r       Runnable[T1, T2]
+	options retryOptions
+}
+
+func (r retry[T1, T2]) Invoke(in T1) (out T2, err error) {
+	for attempt := 1; ; attempt++ {
+		if out, err = r.r.Invoke(in); err == nil || !r.options.shouldRetry(attempt, err) {
+			return
+		}
+		time.Sleep(r.options.delay(attempt))
+	}
+}
+
+// Retry takes a Runnable instance and returns a new Runnable instance that re-invokes it on error.
+// The last error is returned if all attempts fail or the error is not retryable.
+func Retry[T1, T2 any](r Runnable[T1, T2], options ...RetryOption) Runnable[T1, T2] {
+	var o retryOptions
+	o.apply(options)
+	return retry[T1, T2]{r: r, options: o}
+}
+
+type retryCtx[T1, T2 any] struct {
+	r       RunnableContext[T1, T2]
+	options retryOptions
+}
+
+func (r retryCtx[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	for attempt := 1; ; attempt++ {
+		if out, err = r.r.Invoke(ctx, in); err == nil || !r.options.shouldRetry(attempt, err) {
+			return
+		}
+		timer := time.NewTimer(r.options.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return out, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryCtx is like Retry but for RunnableContext instances. Waiting between attempts stops when the context is done.
+func RetryCtx[T1, T2 any](r RunnableContext[T1, T2], options ...RetryOption) RunnableContext[T1, T2] {
+	var o retryOptions
+	o.apply(options)
+	return retryCtx[T1, T2]{r: r, options: o}
+}
@@ -0,0 +1,56 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestRetry(t *testing.T) {
+	var attempts int
+	r := chain.Retry(chain.Func2(func(s string) (int, error) {
+		attempts++
+		if attempts < 3 {
+			return 0, errors.New("flaky")
+		}
+		return len(s), nil
+	}), chain.WithMaxAttempts(5), chain.WithBackoff(time.Millisecond, 5*time.Millisecond, 2))
+	out, err := r.Invoke("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != 5 || attempts != 3 {
+		t.Fatalf("expected: 5 after 3 attempts, got: %d after %d attempts", out, attempts)
+	}
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	errFatal := errors.New("fatal")
+	var attempts int
+	r := chain.Retry(chain.Func2(func(s string) (int, error) {
+		attempts++
+		return 0, errFatal
+	}), chain.WithBackoff(0, 0, 1), chain.WithRetryIf(func(err error) bool {
+		return !errors.Is(err, errFatal)
+	}))
+	if _, err := r.Invoke("hello"); !errors.Is(err, errFatal) {
+		t.Fatalf("expected: %v, got: %v", errFatal, err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected: 1 attempt, got: %d", attempts)
+	}
+}
+
+func TestRetryCtx_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r := chain.RetryCtx(chain.FuncCtx2(func(ctx context.Context, s string) (int, error) {
+		return 0, errors.New("flaky")
+	}), chain.WithMaxAttempts(100), chain.WithBackoff(time.Second, time.Second, 1))
+	if _, err := r.Invoke(ctx, "hello"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected: %v, got: %v", context.DeadlineExceeded, err)
+	}
+}
@@ -0,0 +1,57 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// source is a type that wraps a function that produces a value.
+type source[T any] func() (T, error)
+
+func (f source[T]) Invoke(struct{}) (out T, err error) {
+	return f()
+}
+
+// Source takes a function that produces a value and returns a Runnable instance that wraps the function.
+// The function returns io.EOF when it has no more values (see Drive).
+func Source[T any](f func() (T, error)) Runnable[struct{}, T] {
+	return source[T](f)
+}
+
+// sink is a type that wraps a function that consumes a value.
+type sink[T any] func(T) error
+
+func (f sink[T]) Invoke(in T) (out struct{}, err error) {
+	return out, f(in)
+}
+
+// Sink takes a function that consumes a value and returns a Runnable instance that wraps the function.
+func Sink[T any](f func(T) error) Runnable[T, struct{}] {
+	return sink[T](f)
+}
+
+// Drive repeatedly pulls a value from the source, passes it through the pipeline and pushes the result
+// to the sink, until the source returns io.EOF or the context is done. It returns nil if the source is
+// exhausted, the context error if the context is done, or the first error returned by any stage.
+func Drive[T1, T2 any](ctx context.Context, source Runnable[struct{}, T1], pipeline Runnable[T1, T2], sink Runnable[T2, struct{}]) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		x, err := source.Invoke(struct{}{})
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		y, err := pipeline.Invoke(x)
+		if err != nil {
+			return err
+		}
+		if _, err := sink.Invoke(y); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestDrive(t *testing.T) {
+	inputs := []int{1, 2, 3}
+	src := chain.Source(func() (int, error) {
+		if len(inputs) == 0 {
+			return 0, io.EOF
+		}
+		x := inputs[0]
+		inputs = inputs[1:]
+		return x, nil
+	})
+	var outputs []string
+	dst := chain.Sink(func(s string) error {
+		outputs = append(outputs, s)
+		return nil
+	})
+	if err := chain.Drive(context.Background(), src, chain.Func(strconv.Itoa), dst); err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 3 || outputs[0] != "1" || outputs[2] != "3" {
+		t.Fatalf("expected: [1 2 3], got: %v", outputs)
+	}
+}
+
+func TestDrive_Error(t *testing.T) {
+	errFull := errors.New("full")
+	src := chain.Source(func() (int, error) {
+		return 1, nil
+	})
+	dst := chain.Sink(func(i int) error {
+		return errFull
+	})
+	if err := chain.Drive(context.Background(), src, chain.Identity[int](), dst); !errors.Is(err, errFull) {
+		t.Fatalf("expected: %v, got: %v", errFull, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := chain.Drive(ctx, src, chain.Identity[int](), dst); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected: %v, got: %v", context.Canceled, err)
+	}
+}
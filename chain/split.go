@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+type splitOptions[T any] struct {
+	key func(T) string
+}
+
+// SplitOption is a configuration option for the Split function.
+type SplitOption[T any] func(*splitOptions[T])
+
+// WithSplitKey sets the function that extracts the routing key of an input. Inputs with the same key
+// are always routed to the same Runnable instance. By default, inputs are routed randomly.
+func WithSplitKey[T any](f func(T) string) SplitOption[T] {
+	if f == nil {
+		panic("nil function for WithSplitKey")
+	}
+	return func(o *splitOptions[T]) {
+		o.key = f
+	}
+}
+
+type splitter[T1, T2 any] struct {
+	fraction float64
+	a, b     Runnable[T1, T2]
+	key      func(T1) string
+}
+
+func (s splitter[T1, T2]) Invoke(in T1) (out T2, err error) {
+	var p float64
+	if s.key != nil {
+		h := fnv.New64a()
+		h.Write([]byte(s.key(in)))
+		// Use the top 53 bits so that p is uniformly distributed in [0, 1).
+		p = float64(h.Sum64()>>11) / (1 << 53)
+	} else {
+		p = rand.Float64()
+	}
+	if p < s.fraction {
+		return s.b.Invoke(in)
+	}
+	return s.a.Invoke(in)
+}
+
+// Split takes a fraction in the range [0, 1] and two Runnable instances and returns a new Runnable instance
+// that routes the given fraction of invocations to b and the rest to a, for example to canary a new
+// implementation b against the current implementation a.
+func Split[T1, T2 any](fraction float64, a, b Runnable[T1, T2], options ...SplitOption[T1]) Runnable[T1, T2] {
+	if fraction < 0 || fraction > 1 {
+		panic("fraction out of range for Split")
+	}
+	var o splitOptions[T1]
+	for _, opt := range options {
+		opt(&o)
+	}
+	return splitter[T1, T2]{fraction: fraction, a: a, b: b, key: o.key}
+}
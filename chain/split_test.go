@@ -0,0 +1,46 @@
+package chain_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestSplit(t *testing.T) {
+	a := chain.Const[int]("a")
+	b := chain.Const[int]("b")
+	r := chain.Split(0.2, a, b)
+	var nb int
+	const n = 10000
+	for i := 0; i < n; i++ {
+		if out, _ := r.Invoke(i); out == "b" {
+			nb++
+		}
+	}
+	if nb < n*15/100 || nb > n*25/100 {
+		t.Fatalf("expected: about 20%% routed to b, got: %d of %d", nb, n)
+	}
+
+	for _, fraction := range []float64{0, 1} {
+		r := chain.Split(fraction, a, b)
+		want := map[float64]string{0: "a", 1: "b"}[fraction]
+		for i := 0; i < 100; i++ {
+			if out, _ := r.Invoke(i); out != want {
+				t.Fatalf("expected: %s for fraction %v, got: %s", want, fraction, out)
+			}
+		}
+	}
+}
+
+func TestSplit_Key(t *testing.T) {
+	r := chain.Split(0.5, chain.Const[int]("a"), chain.Const[int]("b"), chain.WithSplitKey(strconv.Itoa))
+	for i := 0; i < 100; i++ {
+		first, _ := r.Invoke(i)
+		for j := 0; j < 3; j++ {
+			if out, _ := r.Invoke(i); out != first {
+				t.Fatalf("expected: key %d always routed to %s, got: %s", i, first, out)
+			}
+		}
+	}
+}
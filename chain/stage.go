@@ -0,0 +1,155 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrTypeMismatch is returned when a middleware passes a value of the wrong type to a stage or returns
+// an output of the wrong type from it.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// cell holds an intermediate value of a flattened chain. Passing values through typed cells
+// instead of interfaces avoids boxing them, so invoking a flattened chain does not allocate.
+type cell[T any] struct {
+	v T
+}
+
+// stage is a type-erased Runnable used to execute flattened chains in a single loop.
+type stage struct {
+	// name is the name of the stage (see Named), or empty.
+	name string
+	// in and out are the input and output types of the stage.
+	in, out reflect.Type
+	// invoke invokes the stage with a boxed input. It is used by middlewares and tracing.
+	invoke func(any) (any, error)
+	// run is the fast path: it takes the input from the *cell[T1] in and stores the output in the *cell[T2] out.
+	run func(in, out any) error
+	// newIn and newOut allocate the input and output cells of the stage.
+	newIn, newOut func() any
+	// take returns the boxed value of the input cell and clears the cell.
+	take func(in any) any
+	// put stores a boxed value in the output cell.
+	put func(out any, v any) error
+}
+
+// erase converts a Runnable instance to a stage.
+func erase[T1, T2 any](r Runnable[T1, T2]) stage {
+	return stage{
+		name: nameOf(r),
+		in:   reflect.TypeFor[T1](),
+		out:  reflect.TypeFor[T2](),
+		invoke: func(in any) (any, error) {
+			x, err := unbox[T1](in)
+			if err != nil {
+				var zero T2
+				return zero, err
+			}
+			return r.Invoke(x)
+		},
+		run: func(in, out any) error {
+			c := in.(*cell[T1])
+			x := c.v
+			c.v = *new(T1)
+			y, err := r.Invoke(x)
+			if err != nil {
+				return err
+			}
+			out.(*cell[T2]).v = y
+			return nil
+		},
+		newIn: func() any {
+			return new(cell[T1])
+		},
+		newOut: func() any {
+			return new(cell[T2])
+		},
+		take: func(in any) any {
+			c := in.(*cell[T1])
+			x := c.v
+			c.v = *new(T1)
+			return x
+		},
+		put: func(out any, v any) error {
+			y, err := unbox[T2](v)
+			if err != nil {
+				return err
+			}
+			out.(*cell[T2]).v = y
+			return nil
+		},
+	}
+}
+
+// label returns the name of the stage, or its index if the stage is not named.
+func (s stage) label(index int) string {
+	if s.name != "" {
+		return s.name
+	}
+	return strconv.Itoa(index)
+}
+
+// withInvoke returns a copy of the stage that invokes f instead of the original Runnable instance.
+// The fast path of the returned stage boxes the values passed to f.
+func (s stage) withInvoke(f func(any) (any, error)) stage {
+	s.invoke = f
+	s.run = func(in, out any) error {
+		y, err := f(s.take(in))
+		if err != nil {
+			return err
+		}
+		return s.put(out, y)
+	}
+	return s
+}
+
+// unbox returns the value of type T boxed in v. A nil v is the zero value of T.
+func unbox[T any](v any) (T, error) {
+	if v == nil {
+		var zero T
+		return zero, nil
+	}
+	x, ok := v.(T)
+	if !ok {
+		return x, fmt.Errorf("%w: expected %v, got %T", ErrTypeMismatch, reflect.TypeFor[T](), v)
+	}
+	return x, nil
+}
+
+// stager is implemented by composed Runnable instances that expose their stages.
+type stager interface {
+	flatten() []stage
+}
+
+// stagesOf returns the stages of the Runnable instance r, or r itself as a single stage.
+func stagesOf[T1, T2 any](r Runnable[T1, T2]) []stage {
+	if s, ok := r.(stager); ok {
+		return s.flatten()
+	}
+	return []stage{erase(r)}
+}
+
+// composed reports whether any of the Runnable instances exposes its stages.
+func composed(rs ...any) bool {
+	for _, r := range rs {
+		if _, ok := r.(stager); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// appendStages returns a new slice containing the stages of all slices in order.
+func appendStages(ss ...[]stage) []stage {
+	var n int
+	for _, s := range ss {
+		n += len(s)
+	}
+	stages := make([]stage, 0, n)
+	for _, s := range ss {
+		stages = append(stages, s...)
+	}
+	return stages
+}
@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"context"
+	"sync"
+)
+
+// State is a per-invocation bag of values shared across the stages of a context-aware chain.
+// Stages use it to stash metadata, such as request ids, timings or annotations, without widening
+// the intermediate types of the chain. State is safe for concurrent use.
+//
+// A Middleware does not receive the context of the invocation, so it cannot reach the State:
+// stages that need both should be written as RunnableContext instances that call StateFromContext.
+type State struct {
+	mu     sync.RWMutex
+	values map[string]any
+}
+
+// NewState creates a new empty State.
+func NewState() *State {
+	return &State{values: make(map[string]any)}
+}
+
+// Set sets the value of the key.
+func (s *State) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Get returns the value of the key and whether it exists.
+func (s *State) Get(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Delete deletes the key.
+func (s *State) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Lookup returns the value of the key in the State if it exists and has type T.
+func Lookup[T any](s *State, key string) (T, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	x, ok := v.(T)
+	return x, ok
+}
+
+type stateKey struct{}
+
+// ContextWithState returns a copy of ctx that carries the State s.
+func ContextWithState(ctx context.Context, s *State) context.Context {
+	return context.WithValue(ctx, stateKey{}, s)
+}
+
+// StateFromContext returns the State carried by ctx, or nil.
+func StateFromContext(ctx context.Context) *State {
+	s, _ := ctx.Value(stateKey{}).(*State)
+	return s
+}
+
+type stateful[T1, T2 any] struct {
+	r RunnableContext[T1, T2]
+}
+
+func (s stateful[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	if StateFromContext(ctx) == nil {
+		ctx = ContextWithState(ctx, NewState())
+	}
+	return s.r.Invoke(ctx, in)
+}
+
+// Stateful takes a RunnableContext instance and returns a new RunnableContext instance that attaches
+// a new State to the context of each invocation, unless the context already carries one.
+// Stages of r retrieve the State with StateFromContext.
+func Stateful[T1, T2 any](r RunnableContext[T1, T2]) RunnableContext[T1, T2] {
+	return stateful[T1, T2]{r: r}
+}
@@ -0,0 +1,37 @@
+package chain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestStateful(t *testing.T) {
+	r := chain.Stateful(chain.ChainCtx2(chain.FuncCtx(func(ctx context.Context, s string) int {
+		chain.StateFromContext(ctx).Set("request_id", "r-"+s)
+		return len(s)
+	}), chain.FuncCtx(func(ctx context.Context, n int) string {
+		id, _ := chain.Lookup[string](chain.StateFromContext(ctx), "request_id")
+		return id
+	})))
+	out, err := r.Invoke(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "r-abc" {
+		t.Fatalf("expected: r-abc, got: %s", out)
+	}
+
+	// A State carried by the context is reused, so the caller can read it after the invocation.
+	state := chain.NewState()
+	if _, err := r.Invoke(chain.ContextWithState(context.Background(), state), "xyz"); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := chain.Lookup[string](state, "request_id"); !ok || id != "r-xyz" {
+		t.Fatalf("expected: r-xyz, got: %v", id)
+	}
+	if _, ok := chain.Lookup[int](state, "request_id"); ok {
+		t.Fatal("expected: type mismatch")
+	}
+}
@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"iter"
+	"slices"
+)
+
+// Stream is a Runnable instance that consumes a sequence and lazily produces another sequence.
+// Streams can be chained like any other Runnable instance, so unbounded data can flow through
+// a chain without being materialized in slices.
+type Stream[T1, T2 any] interface {
+	Runnable[iter.Seq[T1], iter.Seq[T2]]
+}
+
+// streamFunc is a type that wraps a function that transforms a sequence.
+type streamFunc[T1, T2 any] func(iter.Seq[T1]) iter.Seq[T2]
+
+func (f streamFunc[T1, T2]) Invoke(in iter.Seq[T1]) (out iter.Seq[T2], err error) {
+	return f(in), nil
+}
+
+// StreamFunc is a function that takes a function that transforms a sequence and returns a Stream instance that wraps the function.
+func StreamFunc[F ~func(iter.Seq[T1]) iter.Seq[T2], T1, T2 any](f F) Stream[T1, T2] {
+	return streamFunc[T1, T2](f)
+}
+
+type lifted[T1, T2 any] struct {
+	r       Runnable[T1, T2]
+	onError func(T1, error) bool
+}
+
+func (l lifted[T1, T2]) Invoke(in iter.Seq[T1]) (out iter.Seq[T2], err error) {
+	return func(yield func(T2) bool) {
+		for x := range in {
+			y, err := l.r.Invoke(x)
+			if err != nil {
+				if !l.onError(x, err) {
+					return
+				}
+				continue
+			}
+			if !yield(y) {
+				return
+			}
+		}
+	}, nil
+}
+
+// Lift takes an element Runnable instance and returns a Stream instance that invokes it for each element of the sequence.
+//
+// If an invocation fails, onError is called with the element and the error: returning true skips the element,
+// returning false stops the output sequence. Use LiftErr to propagate the error to the end of the chain instead.
+func Lift[T1, T2 any](r Runnable[T1, T2], onError func(T1, error) bool) Stream[T1, T2] {
+	if onError == nil {
+		panic("nil error handler for Lift")
+	}
+	return lifted[T1, T2]{r: r, onError: onError}
+}
+
+type liftedErr[T1, T2 any] struct {
+	r Runnable[T1, T2]
+}
+
+func (l liftedErr[T1, T2]) Invoke(in iter.Seq[T1]) (out iter.Seq2[T2, error], err error) {
+	return func(yield func(T2, error) bool) {
+		for x := range in {
+			y, err := l.r.Invoke(x)
+			if err != nil {
+				yield(y, err)
+				return
+			}
+			if !yield(y, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+// LiftErr is like Lift, but the output sequence carries the error of each invocation. The sequence
+// stops after the first error, which CollectErr returns at the end of the chain.
+func LiftErr[T1, T2 any](r Runnable[T1, T2]) Runnable[iter.Seq[T1], iter.Seq2[T2, error]] {
+	return liftedErr[T1, T2]{r: r}
+}
+
+// Values returns a Runnable instance that converts a slice into a sequence of its elements.
+func Values[T any]() Runnable[[]T, iter.Seq[T]] {
+	return fn[[]T, iter.Seq[T]](func(s []T) iter.Seq[T] {
+		return slices.Values(s)
+	})
+}
+
+// Collect returns a Runnable instance that consumes a sequence and collects its elements into a slice.
+func Collect[T any]() Runnable[iter.Seq[T], []T] {
+	return fn[iter.Seq[T], []T](slices.Collect[T])
+}
+
+// CollectErr returns a Runnable instance that consumes a sequence of values and errors and collects the values
+// into a slice. It stops at the first error and returns it with the values collected so far.
+func CollectErr[T any]() Runnable[iter.Seq2[T, error], []T] {
+	return fn2[iter.Seq2[T, error], []T](func(seq iter.Seq2[T, error]) (out []T, err error) {
+		for v, err := range seq {
+			if err != nil {
+				return out, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	})
+}
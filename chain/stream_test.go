@@ -0,0 +1,91 @@
+package chain_test
+
+import (
+	"errors"
+	"iter"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	take := chain.StreamFunc(func(seq iter.Seq[string]) iter.Seq[string] {
+		return func(yield func(string) bool) {
+			n := 0
+			for s := range seq {
+				if n == 3 || !yield(s) {
+					return
+				}
+				n++
+			}
+		}
+	})
+	// The naturals sequence is unbounded, so the stages must be lazy.
+	r := chain.Chain3(chain.Lift(chain.Func(strconv.Itoa), func(int, error) bool { return false }), take, chain.Collect[string]())
+	out, err := r.Invoke(naturals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 3 || out[0] != "0" || out[2] != "2" {
+		t.Fatalf("expected: [0 1 2], got: %v", out)
+	}
+}
+
+func TestLift_Error(t *testing.T) {
+	errOdd := errors.New("odd")
+	half := chain.Func2(func(i int) (int, error) {
+		if i%2 != 0 {
+			return 0, errOdd
+		}
+		return i / 2, nil
+	})
+	var errs []error
+	skip := chain.Lift(half, func(i int, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	out, err := chain.Chain3(chain.Values[int](), skip, chain.Collect[int]()).Invoke([]int{2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0] != 1 || out[1] != 2 {
+		t.Fatalf("expected: [1 2], got: %v", out)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected: 2 errors, got: %d", len(errs))
+	}
+
+	stop := chain.Chain3(chain.Values[int](), chain.LiftErr(half), chain.CollectErr[int]())
+	out, err = stop.Invoke([]int{2, 3, 4})
+	if !errors.Is(err, errOdd) {
+		t.Fatalf("expected: %v, got: %v", errOdd, err)
+	}
+	if len(out) != 1 || out[0] != 1 {
+		t.Fatalf("expected: [1], got: %v", out)
+	}
+	out, err = stop.Invoke([]int{2, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0] != 1 || out[1] != 2 {
+		t.Fatalf("expected: [1 2], got: %v", out)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for nil error handler")
+		}
+	}()
+	chain.Lift(half, nil)
+}
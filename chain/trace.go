@@ -0,0 +1,148 @@
+package chain
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+)
+
+// Span is a single traced operation.
+type Span interface {
+	// SetAttribute sets an attribute of the span.
+	SetAttribute(key string, value any)
+	// End ends the span with the given error or nil.
+	End(err error)
+}
+
+// Tracer starts spans. It is intentionally small so that any tracing backend can be adapted to it.
+//
+// For example, an OpenTelemetry adapter:
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, chain.Span) {
+//		ctx, span := t.t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ s trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value any) {
+//		s.s.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (s otelSpan) End(err error) {
+//		if err != nil {
+//			s.s.RecordError(err)
+//			s.s.SetStatus(codes.Error, err.Error())
+//		}
+//		s.s.End()
+//	}
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns a context containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) End(error)                {}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type tracerHolder struct {
+	Tracer
+}
+
+var globalTracer atomic.Pointer[tracerHolder]
+
+func init() {
+	globalTracer.Store(&tracerHolder{noopTracer{}})
+}
+
+// SetTracer sets the global tracer used by Traced and TraceStages. A nil tracer disables tracing.
+func SetTracer(t Tracer) {
+	if t == nil {
+		t = noopTracer{}
+	}
+	globalTracer.Store(&tracerHolder{t})
+}
+
+// GetTracer returns the global tracer.
+func GetTracer() Tracer {
+	return globalTracer.Load().Tracer
+}
+
+// sizeOf returns the length of a string, slice, array, map or channel value, or -1 for other values.
+func sizeOf(x any) int {
+	switch v := reflect.ValueOf(x); v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return v.Len()
+	default:
+		return -1
+	}
+}
+
+// startSpan starts a span for a stage and records the size of the input if known.
+func startSpan(ctx context.Context, name string, in any) (context.Context, Span) {
+	ctx, span := GetTracer().Start(ctx, name)
+	if n := sizeOf(in); n >= 0 {
+		span.SetAttribute("chain.input.size", n)
+	}
+	return ctx, span
+}
+
+type traced[T1, T2 any] struct {
+	name string
+	r    RunnableContext[T1, T2]
+}
+
+func (t traced[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	ctx, span := startSpan(ctx, t.name, in)
+	defer func() { span.End(err) }()
+	return t.r.Invoke(ctx, in)
+}
+
+// Traced takes a name and a RunnableContext instance and returns a new RunnableContext instance
+// that opens a span with the given name around each invocation using the global tracer.
+func Traced[T1, T2 any](name string, r RunnableContext[T1, T2]) RunnableContext[T1, T2] {
+	return traced[T1, T2]{name: name, r: r}
+}
+
+type tracedStages[T1, T2 any] struct {
+	name   string
+	stages []stage
+}
+
+func (t tracedStages[T1, T2]) Invoke(ctx context.Context, in T1) (out T2, err error) {
+	ctx, span := startSpan(ctx, t.name, in)
+	defer func() { span.End(err) }()
+	var x any = in
+	for i, s := range t.stages {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		_, stageSpan := startSpan(ctx, t.name+"/"+s.label(i), x)
+		stageSpan.SetAttribute("chain.stage", i)
+		x, err = s.invoke(x)
+		stageSpan.End(err)
+		if err != nil {
+			err = stageError(s.name, i, err)
+			return
+		}
+	}
+	out, err = unbox[T2](x)
+	return
+}
+
+// TraceStages takes a name and a Runnable instance and returns a RunnableContext instance that opens a span
+// with the given name around each invocation, and a nested span named name + "/" + stage name (or index, if the stage
+// is not named) around each stage.
+// If r is a chain created by ChainN or a Pipe, every stage is traced; otherwise r is traced as a single stage.
+func TraceStages[T1, T2 any](name string, r Runnable[T1, T2]) RunnableContext[T1, T2] {
+	return tracedStages[T1, T2]{name: name, stages: stagesOf(r)}
+}
@@ -0,0 +1,108 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, chain.Span) {
+	s := &recordedSpan{name: name, attrs: make(map[string]any)}
+	if p, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		s.parent = p.name
+	}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *recordedSpan) End(err error)                      { s.err, s.ended = err, true }
+
+func TestTraceStages(t *testing.T) {
+	tracer := &recordingTracer{}
+	chain.SetTracer(tracer)
+	defer chain.SetTracer(nil)
+
+	errBad := errors.New("bad")
+	r := chain.TraceStages("pipeline", chain.Chain2(chain.Func(func(s string) int {
+		return len(s)
+	}), chain.Func2(func(i int) (string, error) {
+		if i == 0 {
+			return "", errBad
+		}
+		return strconv.Itoa(i), nil
+	})))
+	out, err := r.Invoke(context.Background(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "5" {
+		t.Fatalf("expected: 5, got: %s", out)
+	}
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected: 3 spans, got: %d", len(tracer.spans))
+	}
+	root, s0, s1 := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if root.name != "pipeline" || s0.name != "pipeline/0" || s1.name != "pipeline/1" {
+		t.Fatalf("unexpected span names: %s, %s, %s", root.name, s0.name, s1.name)
+	}
+	if s0.parent != "pipeline" || s1.parent != "pipeline" {
+		t.Fatalf("expected: stage spans nested in pipeline span")
+	}
+	if root.attrs["chain.input.size"] != 5 {
+		t.Fatalf("expected: input size 5, got: %v", root.attrs["chain.input.size"])
+	}
+	for _, s := range tracer.spans {
+		if !s.ended {
+			t.Fatalf("expected: span %s ended", s.name)
+		}
+	}
+
+	tracer.spans = nil
+	if _, err := r.Invoke(context.Background(), ""); !errors.Is(err, errBad) {
+		t.Fatalf("expected: %v, got: %v", errBad, err)
+	}
+	if !errors.Is(tracer.spans[0].err, errBad) || !errors.Is(tracer.spans[2].err, errBad) {
+		t.Fatal("expected: error recorded on spans")
+	}
+}
+
+func TestTraced(t *testing.T) {
+	tracer := &recordingTracer{}
+	chain.SetTracer(tracer)
+	defer chain.SetTracer(nil)
+
+	r := chain.Traced("outer", chain.ChainCtx2(
+		chain.Traced("inner", chain.FuncCtx(func(ctx context.Context, s string) int {
+			return len(s)
+		})),
+		chain.WithContext(chain.Func(strconv.Itoa)),
+	))
+	if _, err := r.Invoke(context.Background(), "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.spans) != 2 || tracer.spans[1].parent != "outer" {
+		t.Fatal("expected: inner span nested in outer span")
+	}
+}
@@ -0,0 +1,28 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gopherd/exp/validate"
+)
+
+// ErrInvalid is the error wrapped by the error returned by Validated when the input fails validation.
+var ErrInvalid = errors.New("validation failed")
+
+type validated[T any] []validate.Rule[T]
+
+func (v validated[T]) Invoke(in T) (out T, err error) {
+	if err := validate.All(in, v...); err != nil {
+		return out, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	return in, nil
+}
+
+// Validated takes validation rules and returns a Runnable instance that passes through its input if all rules
+// accept it. Otherwise, the chain is aborted with an error wrapping ErrInvalid and the errors of all failed rules.
+//
+//	r := chain.Validated(validate.In([]string{"json", "yaml"}))
+func Validated[T any](rules ...validate.Rule[T]) Runnable[T, T] {
+	return validated[T](rules)
+}
@@ -0,0 +1,44 @@
+package chain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+	"github.com/gopherd/exp/validate"
+)
+
+func TestValidated(t *testing.T) {
+	errNegative := errors.New("negative")
+	errOdd := errors.New("odd")
+	var nonNegative validate.Rule[int] = func(i int) error {
+		if i < 0 {
+			return errNegative
+		}
+		return nil
+	}
+	even := func(i int) error {
+		if i%2 != 0 {
+			return errOdd
+		}
+		return nil
+	}
+	r := chain.Validated(nonNegative, even)
+	if out, err := r.Invoke(2); err != nil || out != 2 {
+		t.Fatalf("expected: 2, got: %d, %v", out, err)
+	}
+	_, err := r.Invoke(-3)
+	if !errors.Is(err, chain.ErrInvalid) || !errors.Is(err, errNegative) || !errors.Is(err, errOdd) {
+		t.Fatalf("expected: all validation errors, got: %v", err)
+	}
+}
+
+func TestValidated_In(t *testing.T) {
+	r := chain.Validated(validate.In([]string{"json", "yaml"}))
+	if out, err := r.Invoke("yaml"); err != nil || out != "yaml" {
+		t.Fatalf("expected: yaml, got: %s, %v", out, err)
+	}
+	if _, err := r.Invoke("toml"); !errors.Is(err, chain.ErrInvalid) || !errors.Is(err, validate.ErrNotOneOf) {
+		t.Fatalf("expected: %v, got: %v", validate.ErrNotOneOf, err)
+	}
+}
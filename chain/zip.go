@@ -0,0 +1,76 @@
+package chain
+
+import "sync"
+
+type zipOptions struct {
+	parallel bool
+}
+
+// ZipOption is a configuration option for the Zip function.
+type ZipOption func(*zipOptions)
+
+// WithParallel specifies whether to invoke both Runnable instances of Zip concurrently.
+func WithParallel(parallel bool) ZipOption {
+	return func(o *zipOptions) {
+		o.parallel = parallel
+	}
+}
+
+func (o *zipOptions) apply(opts []ZipOption) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+type zipper[T, A, B, C any] struct {
+	r1       Runnable[T, A]
+	r2       Runnable[T, B]
+	combine  func(A, B) (C, error)
+	parallel bool
+}
+
+func (z zipper[T, A, B, C]) Invoke(in T) (out C, err error) {
+	var (
+		a    A
+		b    B
+		err2 error
+	)
+	if z.parallel {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err2 = z.r2.Invoke(in)
+		}()
+		a, err = z.r1.Invoke(in)
+		wg.Wait()
+	} else if a, err = z.r1.Invoke(in); err == nil {
+		b, err2 = z.r2.Invoke(in)
+	}
+	if err != nil {
+		err = stageError(nameOf(z.r1), 0, err)
+		return
+	}
+	if err2 != nil {
+		err = stageError(nameOf(z.r2), 1, err2)
+		return
+	}
+	return z.combine(a, b)
+}
+
+// Zip takes two Runnable instances and a combine function and returns a new Runnable instance that invokes
+// both Runnable instances with the same input and combines their outputs. If either invocation fails,
+// the error is wrapped like a stage error of a chain (see Named) and combine is not called.
+func Zip[T, A, B, C any](r1 Runnable[T, A], r2 Runnable[T, B], combine func(A, B) (C, error), options ...ZipOption) Runnable[T, C] {
+	if combine == nil {
+		panic("nil combine function for Zip")
+	}
+	var o zipOptions
+	o.apply(options)
+	return zipper[T, A, B, C]{
+		r1:       r1,
+		r2:       r2,
+		combine:  combine,
+		parallel: o.parallel,
+	}
+}
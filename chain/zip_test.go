@@ -0,0 +1,48 @@
+package chain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/chain"
+)
+
+func TestZip(t *testing.T) {
+	length := chain.Func(func(s string) int {
+		return len(s)
+	})
+	upper := chain.Func(strings.ToUpper)
+	combine := func(n int, s string) (string, error) {
+		return strings.Repeat(s, n), nil
+	}
+	for _, parallel := range []bool{false, true} {
+		r := chain.Zip(length, upper, combine, chain.WithParallel(parallel))
+		out, err := r.Invoke("ab")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "ABAB" {
+			t.Fatalf("expected: ABAB, got: %s", out)
+		}
+	}
+}
+
+func TestZip_Error(t *testing.T) {
+	errBad := errors.New("bad")
+	var combined bool
+	r := chain.Zip(chain.Func(func(s string) int {
+		return len(s)
+	}), chain.Named("fail", chain.Func2(func(s string) (string, error) {
+		return "", errBad
+	})), func(n int, s string) (string, error) {
+		combined = true
+		return s, nil
+	}, chain.WithParallel(true))
+	if _, err := r.Invoke("ab"); !errors.Is(err, errBad) {
+		t.Fatalf("expected: %v, got: %v", errBad, err)
+	}
+	if combined {
+		t.Fatal("expected: combine not called")
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
 	"log/slog"
 
 	"github.com/gopherd/core/typing"
@@ -17,8 +18,24 @@ type ClientOptions struct {
 	Scopes Scopes
 	// Name is the namer of the scope: snake_case, camel_case, pascal_case, kebab_case or empty.
 	Namer string
-	// RefreshInterval is the interval to refresh the configuration.
+	// EnvPrefix is the prefix of the environment variables that override configuration values,
+	// e.g. "APP_" for APP_DB__HOST to override db.host, or empty. See EnvOverlay.
+	EnvPrefix string
+	// RefreshInterval is the interval to refresh the configuration, or zero to disable refreshing.
 	RefreshInterval typing.Duration
+	// Watch specifies whether to reload the configuration when the files of a file source
+	// or the keys of a Consul source change.
+	Watch bool
+	// Timeout is the timeout of the requests of HTTP and Consul sources, or zero for no timeout.
+	Timeout typing.Duration
+	// Proxy is the URL of the proxy of HTTP and Consul sources, or empty to use the environment.
+	Proxy string
+	// CAFile is the PEM file of additional CA certificates to trust for HTTPS sources, or empty.
+	CAFile string
+	// CertFile is the PEM file of the client certificate for HTTPS sources, or empty.
+	CertFile string
+	// KeyFile is the PEM file of the private key of the client certificate, or empty.
+	KeyFile string
 }
 
 // Client is the configuration client.
@@ -51,7 +68,9 @@ type Client[H Hub] struct {
 	config  *Config[H]
 	options ClientOptions
 	namer   func(string, string) string
+	tls     *tls.Config
 	handle  spawn.Handle
+	watch   spawn.Handle
 }
 
 // NewClient creates a new configuration client.
@@ -64,6 +83,22 @@ func (c *Client[H]) Latest() H {
 	return c.config.Latest()
 }
 
+// LastLoad returns the status of the last load of the configuration. See Config.LastLoad.
+func (c *Client[H]) LastLoad() *LoadStatus {
+	return c.config.LastLoad()
+}
+
+// OnChange registers f to be called each time the configuration is reloaded. See Config.OnChange.
+func (c *Client[H]) OnChange(f func(old, new H)) (cancel func()) {
+	return c.config.OnChange(f)
+}
+
+// OnDiff registers f to be called with the changes each time the configuration is reloaded.
+// See Config.OnDiff.
+func (c *Client[H]) OnDiff(f func(changes []Change)) (cancel func()) {
+	return c.config.OnDiff(f)
+}
+
 func (c *Client[H]) Init(ctx context.Context) error {
 	switch c.options.Namer {
 	case "snake_case":
@@ -75,33 +110,59 @@ func (c *Client[H]) Init(ctx context.Context) error {
 	case "kebab_case":
 		c.namer = kebabCaseNamer
 	}
-	_, err := c.config.Load(ctx, Options{
-		Source:      c.options.Source,
-		ContentType: c.options.ContentType,
-		Scopes:      c.options.Scopes,
-		Namer:       c.namer,
-	})
+	if c.options.CAFile != "" || c.options.CertFile != "" || c.options.KeyFile != "" {
+		config, err := NewTLSConfig(c.options.CAFile, c.options.CertFile, c.options.KeyFile)
+		if err != nil {
+			return err
+		}
+		c.tls = config
+	}
+	_, err := c.config.Load(ctx, c.loadOptions())
 	return err
 }
 
 func (c *Client[H]) Start(ctx context.Context) error {
-	c.handle = spawn.Tick(ctx, c.reload, c.options.RefreshInterval.Value())
+	if c.options.Watch {
+		watch, err := c.config.Watch(ctx, c.loadOptions())
+		if err != nil {
+			return err
+		}
+		c.watch = watch
+	}
+	if interval := c.options.RefreshInterval.Value(); interval > 0 {
+		c.handle = spawn.Tick(ctx, c.reload, interval)
+	}
 	return nil
 }
 
 func (c *Client[H]) Shutdown(ctx context.Context) error {
-	c.handle.Cancel()
-	c.handle.Join(ctx)
+	for _, h := range []spawn.Handle{c.handle, c.watch} {
+		if h != nil {
+			h.Cancel()
+			h.Join(ctx)
+		}
+	}
 	return nil
 }
 
-func (c *Client[H]) reload(ctx context.Context) {
-	_, err := c.config.Load(ctx, Options{
+func (c *Client[H]) loadOptions() Options {
+	options := Options{
 		Source:      c.options.Source,
 		ContentType: c.options.ContentType,
 		Scopes:      c.options.Scopes,
 		Namer:       c.namer,
-	})
+		Timeout:     c.options.Timeout.Value(),
+		Proxy:       c.options.Proxy,
+		TLSConfig:   c.tls,
+	}
+	if c.options.EnvPrefix != "" {
+		options.Env = &EnvOverlay{Prefix: c.options.EnvPrefix}
+	}
+	return options
+}
+
+func (c *Client[H]) reload(ctx context.Context) {
+	_, err := c.config.Load(ctx, c.loadOptions())
 	if err != nil {
 		slog.Error("failed to load configuration", "error", err)
 	}
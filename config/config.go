@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,7 +14,9 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/gopherd/core/encoding"
@@ -123,11 +127,13 @@ type Options struct {
 	//
 	// - http:// or https://: the data is fetched from the given URL.
 	// - file://: the data is fetched from the given directory.
+	// - consul://: the data is fetched from the keys under the given prefix of the Consul KV store.
 	//
 	// For example:
 	//
 	// - http://example.com/cfg
 	// - file:///etc/cfg
+	// - consul://127.0.0.1:8500/cfg?dc=dc1&token=secret
 	Source string
 
 	// ContentType is the content type of the data or empty (default is "application/json").
@@ -141,6 +147,40 @@ type Options struct {
 
 	// Namer is the function to name the scope. If the Namer is nil, the scope + "." + ext is used.
 	Namer func(scope, ext string) string
+
+	// Sources is the layered sources of the data or empty. If it is not empty, Source and Fetch
+	// are ignored, and the data of each scope is merged from all sources in order: values of later
+	// sources override values of earlier ones, objects are merged recursively, and arrays are replaced
+	// rather than merged. A scope may be missing from some sources, but not from all. For example,
+	// a base configuration shipped in files can be selectively overridden by a remote server.
+	//
+	// Layered sources are fetched in full on each load: the checksum of HTTP sources is not used, but
+	// the merged data is only parsed if it has changed since the last load.
+	Sources []Source
+
+	// Env is the environment variable overlay applied to the loaded data, after merging the
+	// layered sources, or nil.
+	Env *EnvOverlay
+
+	// Retry is the retry policy of remote fetches or nil (default is no retry).
+	Retry *Retry
+
+	// HTTPClient is the HTTP client of HTTP and Consul sources or nil (default is http.DefaultClient).
+	HTTPClient *http.Client
+
+	// Timeout is the timeout of the requests of HTTP and Consul sources or zero (default is the timeout
+	// of the HTTPClient). The wait time of Consul blocking queries is added to it.
+	Timeout time.Duration
+
+	// Proxy is the URL of the proxy of HTTP and Consul sources or empty (default is the proxy of the
+	// HTTPClient, which is taken from the environment for http.DefaultClient).
+	Proxy string
+
+	// TLSConfig is the TLS configuration of HTTPS and Consul sources or nil. See NewTLSConfig.
+	TLSConfig *tls.Config
+
+	// attempts counts the attempts of the remote fetches of a load.
+	attempts *int
 }
 
 func snakeCaseNamer(scope, ext string) string {
@@ -167,9 +207,35 @@ type Hub interface {
 
 // Config is the configuration.
 type Config[H Hub] struct {
-	new      func() H
-	hub      atomic.Pointer[H]
-	checksum string
+	new         func() H
+	hub         atomic.Pointer[H]
+	consulIndex atomic.Uint64
+	status      atomic.Pointer[LoadStatus]
+	client      atomic.Pointer[cachedHTTPClient]
+
+	fileMu     sync.Mutex // serializes loads of file sources
+	fileDigest *[sha256.Size]byte
+
+	httpMu     sync.Mutex // serializes loads of HTTP sources
+	validators httpValidators
+
+	layersMu     sync.Mutex // serializes loads of layered sources
+	layersDigest *[sha256.Size]byte
+
+	swapMu    sync.Mutex  // serializes hub swaps, and guards changes and notifying
+	changes   []change[H] // swaps not yet notified to the listeners
+	notifying bool        // whether a goroutine is calling the listeners
+	mu        sync.Mutex  // guards listeners
+	listeners []*listener[H]
+}
+
+// change is a swap of the hub.
+type change[H Hub] struct {
+	old, new H
+}
+
+type listener[H Hub] struct {
+	f func(old, new H)
 }
 
 // NewConfig creates a new configuration.
@@ -182,17 +248,98 @@ func (c *Config[H]) Latest() H {
 	return *c.hub.Load()
 }
 
-func (c *Config[H]) parse(data []byte, dec encoding.Decoder) error {
+// OnChange registers f to be called each time a load swaps the hub, with the previous hub, or the
+// zero value for the first load, and the new one. Listeners are called in the order of the swaps and
+// in registration order, after the new hub is returned by Latest, and never concurrently. They are
+// called on the goroutine of the load once it has released its locks, so they may load the
+// configuration, unless another goroutine is already calling them: then that goroutine calls them.
+// The returned function unregisters f.
+func (c *Config[H]) OnChange(f func(old, new H)) (cancel func()) {
+	l := &listener[H]{f: f}
+	c.mu.Lock()
+	c.listeners = append(c.listeners, l)
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.listeners = slices.DeleteFunc(c.listeners, func(x *listener[H]) bool { return x == l })
+	}
+}
+
+// parse parses the data into a new hub, after applying the environment overlay if any, and swaps it in.
+// The change is notified to the listeners by notify.
+func (c *Config[H]) parse(data []byte, options Options) error {
+	_, enc, dec, err := options.ContentType.Parse()
+	if err != nil {
+		return err
+	}
+	if options.Env != nil {
+		if data, err = options.Env.apply(data, enc, dec); err != nil {
+			return err
+		}
+	}
 	hub := c.new()
 	if err := hub.Parse(data, dec); err != nil {
 		return err
 	}
-	c.hub.Store(&hub)
+	c.swapMu.Lock()
+	defer c.swapMu.Unlock()
+	var old H
+	if p := c.hub.Swap(&hub); p != nil {
+		old = *p
+	}
+	c.changes = append(c.changes, change[H]{old, hub})
 	return nil
 }
 
-// Load loads the data by the given options.
+// notify calls the listeners with the changes swapped in since the last call, unless another
+// goroutine is already calling them, in which case that goroutine calls them with the changes too.
+func (c *Config[H]) notify() {
+	c.swapMu.Lock()
+	defer c.swapMu.Unlock()
+	if c.notifying {
+		return
+	}
+	c.notifying = true
+	for len(c.changes) > 0 {
+		changes := c.changes
+		c.changes = nil
+		c.swapMu.Unlock()
+		c.mu.Lock()
+		listeners := slices.Clone(c.listeners)
+		c.mu.Unlock()
+		for _, change := range changes {
+			for _, l := range listeners {
+				l.f(change.old, change.new)
+			}
+		}
+		c.swapMu.Lock()
+	}
+	c.notifying = false
+}
+
+// Load loads the data by the given options, and reports whether the configuration changed.
+// Remote fetches are retried according to options.Retry. The status of the load is reported by LastLoad.
 func (c *Config[H]) Load(ctx context.Context, options Options) (bool, error) {
+	var attempts int
+	options.attempts = &attempts
+	changed, err := c.load(ctx, options)
+	c.setStatus(attempts, err)
+	c.notify()
+	return changed, err
+}
+
+// setStatus records the status of a load, reported by LastLoad.
+func (c *Config[H]) setStatus(attempts int, err error) {
+	c.status.Store(&LoadStatus{Time: time.Now(), Attempts: attempts, Err: err})
+}
+
+// LastLoad returns the status of the last load, or nil if the configuration has never been loaded.
+func (c *Config[H]) LastLoad() *LoadStatus {
+	return c.status.Load()
+}
+
+func (c *Config[H]) load(ctx context.Context, options Options) (bool, error) {
 	options.Scopes = options.Scopes.Compact()
 	if len(options.Scopes) == 0 {
 		return false, nil
@@ -202,101 +349,198 @@ func (c *Config[H]) Load(ctx context.Context, options Options) (bool, error) {
 			return false, fmt.Errorf("scope * should be resolved before loading")
 		}
 	}
+	if len(options.Sources) > 0 {
+		return c.loadLayers(ctx, options)
+	}
 	if options.Fetch != nil {
-		_, _, dec, err := options.ContentType.Parse()
-		if err != nil {
+		if _, _, _, err := options.ContentType.Parse(); err != nil {
 			return false, err
 		}
-		data, err := options.Fetch(options.ContentType, options.Scopes)
+		var data []byte
+		err := options.Retry.do(ctx, options.attempts, func() (err error) {
+			data, err = options.Fetch(options.ContentType, options.Scopes)
+			return err
+		})
 		if err != nil {
 			return false, err
 		}
-		return true, c.parse(data, dec)
+		return true, c.parse(data, options)
 	}
 	if strings.HasPrefix(options.Source, "http://") || strings.HasPrefix(options.Source, "https://") {
 		return c.loadHTTP(ctx, options)
 	}
-	if !strings.HasPrefix(options.Source, "file://") {
-		dir, err := filepath.Abs(options.Source)
-		if err != nil {
-			return false, err
-		}
-		options.Source = "file://" + dir + "?ext=json"
+	if strings.HasPrefix(options.Source, "consul://") {
+		return c.loadConsul(ctx, options)
 	}
-	return true, c.loadDir(options)
+	return c.loadDir(options)
 }
 
-// loadDir loads the data from the directory.
-func (c *Config[H]) loadDir(options Options) error {
-	u, err := url.Parse(options.Source)
+// sourceDir returns the directory of a file source: a file:// URL or a path.
+func sourceDir(source string) (string, error) {
+	if !strings.HasPrefix(source, "file://") {
+		return filepath.Abs(source)
+	}
+	u, err := url.Parse(source)
 	if err != nil {
-		return err
+		return "", err
 	}
-	dir := u.Path
-	ext, _, dec, err := options.ContentType.Parse()
+	return u.Path, nil
+}
+
+// scopeFiles returns the path of the file of each scope of a file source.
+func scopeFiles(options Options) (map[string]string, error) {
+	dir, err := sourceDir(options.Source)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	ext, _, _, err := options.ContentType.Parse()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(options.Scopes))
+	for _, scope := range options.Scopes {
+		files[scope] = filepath.Join(dir, scopeName(options, scope, ext))
+	}
+	return files, nil
+}
+
+// scopeName returns the name of the file or key of the scope.
+func scopeName(options Options, scope, ext string) string {
+	if options.Namer != nil {
+		return options.Namer(scope, ext)
+	}
+	return scope + "." + ext
+}
+
+// loadDir loads the data from the directory. It skips parsing if the content of the files, and the
+// environment variables of the overlay, have not changed since the last load. Loads are serialized,
+// so that concurrent loads neither parse the same files twice nor swap in older files.
+func (c *Config[H]) loadDir(options Options) (bool, error) {
+	c.fileMu.Lock()
+	defer c.fileMu.Unlock()
+	files, err := scopeFiles(options)
+	if err != nil {
+		return false, err
 	}
 	data := make(map[string]json.RawMessage)
-	scopes := []string(options.Scopes)
-	for _, scope := range scopes {
-		var name string
-		if options.Namer != nil {
-			name = options.Namer(scope, ext)
-		} else {
-			name = scope + "." + ext
-		}
-		content, err := os.ReadFile(filepath.Join(dir, name))
+	for scope, path := range files {
+		content, err := os.ReadFile(path)
 		if err != nil {
-			return err
+			return false, err
 		}
 		data[scope] = content
 	}
+	h := sha256.New()
+	for _, scope := range options.Scopes {
+		fmt.Fprintf(h, "%s\x00%d\x00", files[scope], len(data[scope]))
+		h.Write(data[scope])
+	}
+	if options.Env != nil {
+		for _, kv := range options.Env.vars() {
+			fmt.Fprintf(h, "%s\x00", kv)
+		}
+	}
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+	if c.fileDigest != nil && *c.fileDigest == digest {
+		return false, nil
+	}
+	if err := c.parseScopes(data, options); err != nil {
+		return false, err
+	}
+	c.fileDigest = &digest
+	return true, nil
+}
+
+// parseScopes parses the contents of the scopes as an object keyed by scope.
+func (c *Config[H]) parseScopes(data map[string]json.RawMessage, options Options) error {
 	content, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	return c.parse(content, dec)
+	return c.parse(content, options)
 }
 
-// loadHTTP loads the data from the HTTP.
+// loadHTTP loads the data from the HTTP. It skips parsing if the server reports that the data has
+// not changed since the last load, with the X-Checksum header or a 304 Not Modified response to
+// the ETag and Last-Modified validators of the last load. Loads are serialized, so that concurrent
+// loads neither send outdated validators nor swap in older data.
 func (c *Config[H]) loadHTTP(ctx context.Context, options Options) (bool, error) {
-	_, _, dec, err := options.ContentType.Parse()
-	if err != nil {
+	if _, _, _, err := options.ContentType.Parse(); err != nil {
 		return false, err
 	}
-	checksum := c.checksum
-	newChecksum, data, err := fetch(ctx, checksum, options.Source, string(options.ContentType), options.Scopes)
+	client, err := c.httpClient(options)
 	if err != nil {
 		return false, err
 	}
-	if newChecksum == checksum {
+	c.httpMu.Lock()
+	defer c.httpMu.Unlock()
+	validators := c.validators
+	var newValidators httpValidators
+	var data []byte
+	err = options.Retry.do(ctx, options.attempts, func() (err error) {
+		newValidators, data, err = fetch(ctx, client, validators, options.Source, string(options.ContentType), options.Scopes)
+		return err
+	})
+	if err == errNotModified {
 		return false, nil
+	} else if err != nil {
+		return false, err
 	}
-	if err := c.parse(data, dec); err != nil {
+	if newValidators.checksum != "" && newValidators.checksum == validators.checksum {
+		return false, nil
+	}
+	if err := c.parse(data, options); err != nil {
 		return false, err
 	}
-	c.checksum = newChecksum
+	c.validators = newValidators
 	return true, nil
 }
 
-func fetch(ctx context.Context, checksum, url, contentType string, scopes Scopes) (newChecksum string, body []byte, err error) {
+// httpValidators are the values of the headers of an HTTP response used to tell whether the data
+// has changed since the response.
+type httpValidators struct {
+	checksum     string
+	etag         string
+	lastModified string
+}
+
+// errNotModified is returned by fetch for a 304 Not Modified response.
+var errNotModified = errors.New("not modified")
+
+func fetch(ctx context.Context, client *http.Client, validators httpValidators, url, contentType string, scopes Scopes) (newValidators httpValidators, body []byte, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, strings.NewReader(scopes.String()))
 	if err != nil {
 		return
 	}
-	req.Header.Set(HeaderChecksum, checksum)
+	req.Header.Set(HeaderChecksum, validators.checksum)
+	if validators.etag != "" {
+		req.Header.Set("If-None-Match", validators.etag)
+	}
+	if validators.lastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.lastModified)
+	}
 	if contentType == "" {
 		contentType = string(ContentTypeJSON)
 	}
 	req.Header.Set("Content-Type", contentType)
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
-		return "", nil, err
+		return newValidators, nil, err
 	}
-	newChecksum = res.Header.Get(HeaderChecksum)
 	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return validators, nil, errNotModified
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return newValidators, nil, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+	newValidators = httpValidators{
+		checksum:     res.Header.Get(HeaderChecksum),
+		etag:         res.Header.Get("ETag"),
+		lastModified: res.Header.Get("Last-Modified"),
+	}
 	body, err = io.ReadAll(res.Body)
 	return
 }
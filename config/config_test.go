@@ -0,0 +1,279 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/core/encoding"
+	"github.com/gopherd/exp/config"
+)
+
+// testHub is a hub that decodes the data into a map keyed by scope.
+type testHub struct {
+	data map[string]any
+}
+
+func (h *testHub) Parse(data []byte, decoder encoding.Decoder) error {
+	return decoder(data, &h.data)
+}
+
+// value returns the value at the path of the hub, e.g. value("app", "db", "host").
+func (h *testHub) value(path ...string) any {
+	var v any = h.data
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// newTestConfig creates a configuration of testHub and a pointer to the number of parsed hubs.
+func newTestConfig() (*config.Config[*testHub], *int) {
+	var parses int
+	c := config.NewConfig(func() *testHub {
+		parses++
+		return &testHub{}
+	})
+	return c, &parses
+}
+
+func TestLoadDir_Unchanged(t *testing.T) {
+	dir := t.TempDir()
+	write := func(data string) {
+		if err := os.WriteFile(filepath.Join(dir, "app.json"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var env []string
+	c, parses := newTestConfig()
+	ctx := context.Background()
+	options := config.Options{
+		Source: "file://" + dir,
+		Scopes: config.Scopes{"app"},
+		Env:    &config.EnvOverlay{Prefix: "APP_", Environ: func() []string { return env }},
+	}
+	// loadAll loads the configuration concurrently and returns the number of loads that changed it.
+	loadAll := func() int {
+		var wg sync.WaitGroup
+		var changes atomic.Int32
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				changed, err := c.Load(ctx, options)
+				if err != nil {
+					t.Error(err)
+				}
+				if changed {
+					changes.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		return int(changes.Load())
+	}
+
+	tests := []struct {
+		name   string
+		update func()
+		want   int
+	}{
+		{"first load", func() { write(`{"port":1}`) }, 1},
+		{"unchanged", func() {}, 0},
+		{"same content rewritten", func() { write(`{"port":1}`) }, 0},
+		{"changed content", func() { write(`{"port":2}`) }, 1},
+		{"changed environment", func() { env = []string{"APP_APP__PORT=3"} }, 1},
+	}
+	var want int
+	for _, tt := range tests {
+		tt.update()
+		if n := loadAll(); n != tt.want {
+			t.Errorf("%s: Expected %d changed loads, got %d", tt.name, tt.want, n)
+		}
+		if want += tt.want; *parses != want {
+			t.Errorf("%s: Expected %d parses, got %d", tt.name, want, *parses)
+		}
+	}
+	if v := c.Latest().value("app", "port"); v != 3.0 {
+		t.Errorf("Expected port 3, got %v", v)
+	}
+}
+
+func TestLoadHTTP_Validators(t *testing.T) {
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	var (
+		mu       sync.Mutex
+		etag     = `"v1"`
+		body     = `{"app":{"port":1}}`
+		requests []http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Header.Clone())
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c, parses := newTestConfig()
+	ctx := context.Background()
+	options := config.Options{Source: server.URL, Scopes: config.Scopes{"app"}}
+	if changed, err := c.Load(ctx, options); err != nil || !changed {
+		t.Fatalf("Expected changed configuration, got %v, %v", changed, err)
+	}
+	if h := requests[0]; h.Get("If-None-Match") != "" || h.Get("If-Modified-Since") != "" {
+		t.Errorf("Expected no validators on the first request, got %v", h)
+	}
+
+	// The server reports that the data has not changed, so it is not parsed again.
+	if changed, err := c.Load(ctx, options); err != nil || changed || *parses != 1 {
+		t.Errorf("Expected unchanged configuration parsed once, got %v, %v, %d parses", changed, err, *parses)
+	}
+	if h := requests[1]; h.Get("If-None-Match") != `"v1"` || h.Get("If-Modified-Since") != lastModified {
+		t.Errorf("Expected the validators of the last response, got %v", h)
+	}
+	if s := c.LastLoad(); s == nil || s.Err != nil {
+		t.Errorf("Expected a successful load status, got %+v", s)
+	}
+
+	mu.Lock()
+	etag, body = `"v2"`, `{"app":{"port":2}}`
+	mu.Unlock()
+	if changed, err := c.Load(ctx, options); err != nil || !changed || *parses != 2 {
+		t.Errorf("Expected changed configuration parsed twice, got %v, %v, %d parses", changed, err, *parses)
+	}
+	if v := c.Latest().value("app", "port"); v != 2.0 {
+		t.Errorf("Expected port 2, got %v", v)
+	}
+
+	// Concurrent loads, e.g. by Watch and a refresh interval, share the validators.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Load(ctx, options); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if *parses != 2 {
+		t.Errorf("Expected no more parses, got %d", *parses)
+	}
+}
+
+func TestLoadHTTP_Checksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(config.HeaderChecksum, "c1")
+		w.Write([]byte(`{"app":{}}`))
+	}))
+	defer server.Close()
+
+	c, parses := newTestConfig()
+	options := config.Options{Source: server.URL, Scopes: config.Scopes{"app"}}
+	for range 2 {
+		if _, err := c.Load(context.Background(), options); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *parses != 1 {
+		t.Errorf("Expected the data with the same checksum to be parsed once, got %d parses", *parses)
+	}
+}
+
+// port returns the port of the app scope of the hub, or nil if the hub is nil.
+func port(h *testHub) any {
+	if h == nil {
+		return nil
+	}
+	return h.value("app", "port")
+}
+
+func TestOnChange(t *testing.T) {
+	data := `{"app":{"port":1}}`
+	options := config.Options{
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			return []byte(data), nil
+		},
+		Scopes: config.Scopes{"app"},
+	}
+	c, _ := newTestConfig()
+	var calls []string
+	listen := func(name string) func() {
+		return c.OnChange(func(old, new *testHub) {
+			calls = append(calls, fmt.Sprintf("%s: %v -> %v", name, port(old), port(new)))
+		})
+	}
+	cancelA := listen("a")
+	listen("b")
+	load := func(want ...string) {
+		t.Helper()
+		calls = nil
+		if _, err := c.Load(context.Background(), options); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("Expected calls %q, got %q", want, calls)
+		}
+	}
+
+	load("a: <nil> -> 1", "b: <nil> -> 1")
+	data = `{"app":{"port":2}}`
+	load("a: 1 -> 2", "b: 1 -> 2")
+	cancelA()
+	data = `{"app":{"port":3}}`
+	load("b: 2 -> 3")
+	cancelA()
+	data = `{"app":{"port":4}}`
+	load("b: 3 -> 4")
+}
+
+func TestOnChange_Load(t *testing.T) {
+	data := `{"app":{"port":1}}`
+	options := config.Options{
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			return []byte(data), nil
+		},
+		Scopes: config.Scopes{"app"},
+	}
+	c, _ := newTestConfig()
+	var calls []string
+	c.OnChange(func(old, new *testHub) {
+		calls = append(calls, fmt.Sprintf("%v -> %v", port(old), port(new)))
+		if port(new) == 1.0 {
+			// A listener may reload the configuration: the listeners are called with the new
+			// change once they return.
+			data = `{"app":{"port":2}}`
+			if _, err := c.Load(context.Background(), options); err != nil {
+				t.Error(err)
+			}
+			if port(c.Latest()) != 2.0 {
+				t.Errorf("Expected the reload to swap the hub, got port %v", port(c.Latest()))
+			}
+		}
+	})
+	if _, err := c.Load(context.Background(), options); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"<nil> -> 1", "1 -> 2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %q, got %q", want, calls)
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+const (
+	// HeaderConsulIndex is the header of the index of a Consul blocking query.
+	HeaderConsulIndex = "X-Consul-Index"
+	// HeaderConsulToken is the header of the ACL token of a Consul request.
+	HeaderConsulToken = "X-Consul-Token"
+)
+
+// ConsulWait is the maximum duration of a Consul blocking query made by Watch.
+const ConsulWait = 5 * time.Minute
+
+// consulRetryDelay is the delay before a blocking query is retried after it failed or did not block.
+const consulRetryDelay = time.Second
+
+// errMissingConsulIndex is the error of a Consul response without the X-Consul-Index header,
+// which is required by blocking queries.
+var errMissingConsulIndex = errors.New("missing " + HeaderConsulIndex + " header")
+
+// consulSource is a consul:// source: consul://host:port/prefix?dc=dc1&token=secret&scheme=https.
+// The token defaults to the CONSUL_HTTP_TOKEN environment variable.
+type consulSource struct {
+	client *http.Client
+	addr   string
+	prefix string
+	dc     string
+	token  string
+}
+
+// consulPair is a key-value pair of the Consul KV API. The value is base64-encoded in JSON.
+type consulPair struct {
+	Key   string
+	Value []byte
+}
+
+func parseConsulSource(source string, client *http.Client) (*consulSource, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	scheme := q.Get("scheme")
+	if scheme == "" {
+		scheme = "http"
+	}
+	s := &consulSource{
+		client: client,
+		addr:   scheme + "://" + u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		dc:     q.Get("dc"),
+		token:  q.Get("token"),
+	}
+	if s.token == "" {
+		s.token = os.Getenv("CONSUL_HTTP_TOKEN")
+	}
+	return s, nil
+}
+
+// key returns the key of the scope with the given name.
+func (s *consulSource) key(name string) string {
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+// list lists the keys under the prefix. If index is not zero, it makes a blocking query that waits
+// up to wait for the index to change.
+func (s *consulSource) list(ctx context.Context, index uint64, wait time.Duration) (pairs []consulPair, newIndex uint64, err error) {
+	q := url.Values{"recurse": {"true"}}
+	if s.dc != "" {
+		q.Set("dc", s.dc)
+	}
+	if index > 0 {
+		q.Set("index", strconv.FormatUint(index, 10))
+		q.Set("wait", strconv.Itoa(int(wait/time.Second))+"s")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/kv/"+s.prefix+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.token != "" {
+		req.Header.Set(HeaderConsulToken, s.token)
+	}
+	client := s.client
+	if index > 0 && client.Timeout > 0 {
+		c := *client
+		c.Timeout += wait
+		client = &c
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+	newIndex, _ = strconv.ParseUint(res.Header.Get(HeaderConsulIndex), 10, 64)
+	switch res.StatusCode {
+	case http.StatusOK:
+		err = json.NewDecoder(res.Body).Decode(&pairs)
+		return pairs, newIndex, err
+	case http.StatusNotFound:
+		return nil, newIndex, nil
+	default:
+		return nil, 0, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+}
+
+// loadConsul loads the data from the Consul KV store. It skips parsing if the index of the keys
+// has not changed since the last load.
+func (c *Config[H]) loadConsul(ctx context.Context, options Options) (bool, error) {
+	client, err := c.httpClient(options)
+	if err != nil {
+		return false, err
+	}
+	s, err := parseConsulSource(options.Source, client)
+	if err != nil {
+		return false, err
+	}
+	var pairs []consulPair
+	var index uint64
+	err = options.Retry.do(ctx, options.attempts, func() (err error) {
+		pairs, index, err = s.list(ctx, 0, 0)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return c.applyConsul(s, options, pairs, index)
+}
+
+// applyConsul parses the keys listed at the index, unless the index has not changed since the last load.
+func (c *Config[H]) applyConsul(s *consulSource, options Options, pairs []consulPair, index uint64) (bool, error) {
+	if index != 0 && index == c.consulIndex.Load() {
+		return false, nil
+	}
+	if err := c.parseConsul(s, options, pairs); err != nil {
+		return false, err
+	}
+	c.consulIndex.Store(index)
+	return true, nil
+}
+
+// parseConsul parses the values of the keys of the scopes.
+func (c *Config[H]) parseConsul(s *consulSource, options Options, pairs []consulPair) error {
+	ext, _, _, err := options.ContentType.Parse()
+	if err != nil {
+		return err
+	}
+	values := make(map[string][]byte, len(pairs))
+	for _, p := range pairs {
+		values[p.Key] = p.Value
+	}
+	data := make(map[string]json.RawMessage, len(options.Scopes))
+	for _, scope := range options.Scopes {
+		key := s.key(scopeName(options, scope, ext))
+		value, ok := values[key]
+		if !ok {
+			return fmt.Errorf("consul key %q: %w", key, ErrNotFound)
+		}
+		data[scope] = value
+	}
+	return c.parseScopes(data, options)
+}
+
+// watchConsul reloads the configuration with Consul blocking queries until the context is canceled.
+// Reloads and failed queries are reported by LastLoad, and queries that fail or do not block are
+// retried after consulRetryDelay.
+func (c *Config[H]) watchConsul(ctx context.Context, options Options) (spawn.Handle, error) {
+	client, err := c.httpClient(options)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseConsulSource(options.Source, client)
+	if err != nil {
+		return nil, err
+	}
+	return spawn.Run(ctx, func(ctx context.Context) {
+		index := max(c.consulIndex.Load(), 1)
+		for ctx.Err() == nil {
+			pairs, newIndex, err := s.list(ctx, index, ConsulWait)
+			if err == nil && newIndex == 0 {
+				err = errMissingConsulIndex
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					c.setStatus(1, err)
+					slog.Error("failed to watch configuration", "error", err)
+					sleep(ctx, consulRetryDelay)
+				}
+				continue
+			}
+			if newIndex <= index {
+				if newIndex < index {
+					// The index went backwards, e.g. after a Consul snapshot restore: reset it.
+					newIndex = 0
+				}
+				// The wait expired without changes, or the query did not block.
+				index = max(newIndex, 1)
+				sleep(ctx, consulRetryDelay)
+				continue
+			}
+			index = newIndex
+			changed, err := c.applyConsul(s, options, pairs, newIndex)
+			if changed || err != nil {
+				c.setStatus(1, err)
+			}
+			c.notify()
+			if err != nil {
+				slog.Error("failed to reload configuration", "error", err)
+			}
+		}
+	}), nil
+}
+
+// sleep waits for the duration d or the context to be canceled.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,199 @@
+package config_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/config"
+)
+
+// fakeConsul is a Consul KV store that answers recursive and blocking queries.
+type fakeConsul struct {
+	mu      sync.Mutex
+	index   uint64
+	values  map[string]string
+	changed chan struct{}
+	noIndex bool
+
+	requests atomic.Int32
+	lastURL  atomic.Pointer[string]
+	token    atomic.Pointer[string]
+}
+
+func newFakeConsul(values map[string]string) *fakeConsul {
+	return &fakeConsul{index: 10, values: values, changed: make(chan struct{})}
+}
+
+func (f *fakeConsul) set(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = value
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	u, token := r.URL.String(), r.Header.Get(config.HeaderConsulToken)
+	f.lastURL.Store(&u)
+	f.token.Store(&token)
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	if index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); index > 0 {
+		f.mu.Lock()
+		current, changed := f.index, f.changed
+		f.mu.Unlock()
+		if index == current {
+			select {
+			case <-changed:
+			case <-time.After(200 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.noIndex {
+		w.Header().Set(config.HeaderConsulIndex, strconv.FormatUint(f.index, 10))
+	}
+	var pairs []string
+	for key, value := range f.values {
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, `{"Key":"`+key+`","Value":"`+encode(value)+`"}`)
+		}
+	}
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte("[" + strings.Join(pairs, ",") + "]"))
+}
+
+// encode returns the base64 encoding of s, as Consul encodes values.
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func consulSource(server *httptest.Server, prefix string) string {
+	return "consul://" + strings.TrimPrefix(server.URL, "http://") + "/" + prefix + "?dc=dc1&token=secret"
+}
+
+func TestConsulLoad(t *testing.T) {
+	fake := newFakeConsul(map[string]string{
+		"cfg/app.json": `{"name":"a"}`,
+		"cfg/db.json":  `{"host":"h"}`,
+		"other/x.json": `{}`,
+	})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c, parses := newTestConfig()
+	ctx := context.Background()
+	options := config.Options{Source: consulSource(server, "cfg"), Scopes: config.Scopes{"app", "db"}}
+	changed, err := c.Load(ctx, options)
+	if err != nil || !changed {
+		t.Fatalf("Expected changed configuration, got %v, %v", changed, err)
+	}
+	if v := c.Latest().value("db", "host"); v != "h" {
+		t.Errorf("Expected db.host h, got %v", v)
+	}
+	if u := *fake.lastURL.Load(); !strings.Contains(u, "dc=dc1") || !strings.Contains(u, "recurse=true") {
+		t.Errorf("Expected dc and recurse parameters, got %s", u)
+	}
+	if token := *fake.token.Load(); token != "secret" {
+		t.Errorf("Expected token secret, got %q", token)
+	}
+
+	// The index has not changed, so the keys are not parsed again.
+	if changed, err := c.Load(ctx, options); err != nil || changed || *parses != 1 {
+		t.Errorf("Expected unchanged configuration parsed once, got %v, %v, %d parses", changed, err, *parses)
+	}
+
+	options.Scopes = config.Scopes{"app", "missing"}
+	fake.set("cfg/app.json", `{"name":"b"}`)
+	if _, err := c.Load(ctx, options); !errors.Is(err, config.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing key, got %v", err)
+	}
+	if s := c.LastLoad(); s == nil || !errors.Is(s.Err, config.ErrNotFound) {
+		t.Errorf("Expected the error in the last load status, got %+v", s)
+	}
+}
+
+func TestConsulWatch(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"cfg/app.json": `{"name":"a"}`})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c, _ := newTestConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := config.Options{Source: consulSource(server, "cfg"), Scopes: config.Scopes{"app"}}
+	if _, err := c.Load(ctx, options); err != nil {
+		t.Fatal(err)
+	}
+	loaded := c.LastLoad()
+	handle, err := c.Watch(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.set("cfg/app.json", `{"name":"b"}`)
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Latest().value("app", "name") != "b" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v := c.Latest().value("app", "name"); v != "b" {
+		t.Fatalf("Expected app.name b after the change, got %v", v)
+	}
+	if s := c.LastLoad(); s == loaded || s.Err != nil {
+		t.Errorf("Expected the reload in the last load status, got %+v", s)
+	}
+
+	// The value of an unloadable key is reported by LastLoad.
+	fake.set("cfg/app.json", `{`)
+	deadline = time.Now().Add(2 * time.Second)
+	for (c.LastLoad().Err == nil) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s := c.LastLoad(); s.Err == nil {
+		t.Errorf("Expected the failed reload in the last load status, got %+v", s)
+	}
+	cancel()
+	handle.Join(context.Background())
+}
+
+func TestConsulWatch_MissingIndex(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"cfg/app.json": `{"name":"a"}`})
+	fake.noIndex = true
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	c, _ := newTestConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	options := config.Options{Source: consulSource(server, "cfg"), Scopes: config.Scopes{"app"}}
+	handle, err := c.Watch(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	cancel()
+	handle.Join(context.Background())
+
+	// Without an index, the query does not block: the watch must back off instead of spinning.
+	if n := fake.requests.Load(); n > 2 {
+		t.Errorf("Expected at most 2 queries without an index, got %d", n)
+	}
+	if s := c.LastLoad(); s == nil || s.Err == nil {
+		t.Errorf("Expected the missing index in the last load status, got %+v", s)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidDefault is the error that a default value cannot be applied.
+var ErrInvalidDefault = errors.New("invalid default value")
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// ApplyDefaults sets the zero fields of the struct pointed to by v to the values of their
+// `default:"..."` struct tags, so that hubs do not need hand-written default handling for every
+// field. It can be called before Parse, so that parsed values override the defaults, or after it,
+// to fill the fields that were not set.
+//
+// Nested structs and non-nil pointers to structs are processed recursively. Tag values are parsed
+// according to the type of the field: strings are used as is, booleans and numbers are parsed with
+// strconv, time.Duration with time.ParseDuration, types implementing encoding.TextUnmarshaler with
+// UnmarshalText, types implementing json.Unmarshaler with UnmarshalJSON, quoting the value if it is
+// not valid for it, and other types, like slices and maps, as JSON.
+//
+// Usage:
+//
+//	type Server struct {
+//		Addr    string        `json:"addr" default:":8080"`
+//		Timeout time.Duration `json:"timeout" default:"5s"`
+//		Tags    []string      `json:"tags" default:"[\"web\"]"`
+//	}
+func ApplyDefaults(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: ApplyDefaults requires a non-nil pointer to a struct, got %T", ErrInvalidDefault, v)
+	}
+	return applyDefaults(rv.Elem())
+}
+
+func applyDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if tag, ok := field.Tag.Lookup("default"); ok && fv.IsZero() {
+			if err := setDefault(fv, tag); err != nil {
+				return fmt.Errorf("%w for field %s: %v", ErrInvalidDefault, field.Name, err)
+			}
+		}
+		switch {
+		case fv.Kind() == reflect.Struct:
+			if err := applyDefaults(fv); err != nil {
+				return err
+			}
+		case fv.Kind() == reflect.Pointer && !fv.IsNil() && fv.Elem().Kind() == reflect.Struct:
+			if err := applyDefaults(fv.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setDefault sets v to the value parsed from s.
+func setDefault(v reflect.Value, s string) error {
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Addr().Type().Implements(jsonUnmarshalerType) {
+		u := v.Addr().Interface().(json.Unmarshaler)
+		if err := u.UnmarshalJSON([]byte(s)); err != nil {
+			// Allow unquoted strings, e.g. "5s" for a duration that unmarshals from a JSON string.
+			return u.UnmarshalJSON([]byte(strconv.Quote(s)))
+		}
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return nil
+}
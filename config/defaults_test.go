@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/config"
+)
+
+// level is a json.Unmarshaler that only accepts JSON strings.
+type level int
+
+func (l *level) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return errors.New("unknown level " + s)
+	}
+	return nil
+}
+
+type defaultsDB struct {
+	Host string `default:"localhost"`
+	Port int    `default:"5432"`
+}
+
+type defaults struct {
+	String   string            `default:"s"`
+	Bool     bool              `default:"true"`
+	Int      int               `default:"-3"`
+	Int8     int8              `default:"0x10"`
+	Uint     uint              `default:"7"`
+	Float32  float32           `default:"1.5"`
+	Float64  float64           `default:"2.5"`
+	Duration time.Duration     `default:"1m30s"`
+	Addr     netip.Addr        `default:"127.0.0.1"`
+	Level    level             `default:"info"`
+	Tags     []string          `default:"[\"a\",\"b\"]"`
+	Labels   map[string]string `default:"{\"k\":\"v\"}"`
+	DB       defaultsDB
+	Cache    *defaultsDB
+	Nil      *defaultsDB
+	NoTag    int
+	hidden   string `default:"x"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	var v defaults
+	v.Cache = &defaultsDB{}
+	if err := config.ApplyDefaults(&v); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"string", v.String, "s"},
+		{"bool", v.Bool, true},
+		{"int", v.Int, -3},
+		{"int8", v.Int8, int8(16)},
+		{"uint", v.Uint, uint(7)},
+		{"float32", v.Float32, float32(1.5)},
+		{"float64", v.Float64, 2.5},
+		{"duration", v.Duration, 90 * time.Second},
+		{"text unmarshaler", v.Addr, netip.MustParseAddr("127.0.0.1")},
+		{"json unmarshaler", v.Level, level(2)},
+		{"slice", v.Tags, []string{"a", "b"}},
+		{"map", v.Labels, map[string]string{"k": "v"}},
+		{"nested struct", v.DB, defaultsDB{Host: "localhost", Port: 5432}},
+		{"pointer to struct", *v.Cache, defaultsDB{Host: "localhost", Port: 5432}},
+		{"nil pointer", v.Nil, (*defaultsDB)(nil)},
+		{"no tag", v.NoTag, 0},
+		{"unexported", v.hidden, ""},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestApplyDefaults_Set(t *testing.T) {
+	v := defaults{
+		String:   "set",
+		Bool:     true,
+		Int:      1,
+		Duration: time.Second,
+		Level:    1,
+		Tags:     []string{},
+		DB:       defaultsDB{Port: 1},
+		Cache:    &defaultsDB{Host: "cache"},
+	}
+	if err := config.ApplyDefaults(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v.String != "set" || v.Int != 1 || v.Duration != time.Second || v.Level != 1 {
+		t.Errorf("Expected set fields not to be overwritten, got %+v", v)
+	}
+	if v.Tags == nil || len(v.Tags) != 0 {
+		t.Errorf("Expected an empty non-nil slice not to be overwritten, got %v", v.Tags)
+	}
+	if v.DB != (defaultsDB{Host: "localhost", Port: 1}) || *v.Cache != (defaultsDB{Host: "cache", Port: 5432}) {
+		t.Errorf("Expected only the zero nested fields to be set, got %+v and %+v", v.DB, *v.Cache)
+	}
+}
+
+func TestApplyDefaults_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"bool", &struct {
+			V bool `default:"maybe"`
+		}{}},
+		{"int", &struct {
+			V int `default:"x"`
+		}{}},
+		{"int overflow", &struct {
+			V int8 `default:"300"`
+		}{}},
+		{"negative uint", &struct {
+			V uint `default:"-1"`
+		}{}},
+		{"float", &struct {
+			V float64 `default:"x"`
+		}{}},
+		{"duration", &struct {
+			V time.Duration `default:"5"`
+		}{}},
+		{"text unmarshaler", &struct {
+			V netip.Addr `default:"localhost"`
+		}{}},
+		{"json unmarshaler", &struct {
+			V level `default:"trace"`
+		}{}},
+		{"json", &struct {
+			V []int `default:"[1,"`
+		}{}},
+		{"nested", &struct {
+			V struct {
+				W int `default:"x"`
+			}
+		}{}},
+		{"not a pointer", struct{}{}},
+		{"nil pointer", (*defaults)(nil)},
+		{"pointer to non-struct", new(int)},
+	}
+	for _, tt := range tests {
+		if err := config.ApplyDefaults(tt.v); !errors.Is(err, config.ErrInvalidDefault) {
+			t.Errorf("%s: Expected ErrInvalidDefault, got %v", tt.name, err)
+		}
+	}
+}
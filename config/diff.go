@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Change is a change of a value between two configurations, as returned by Diff.
+type Change struct {
+	// Path is the path of the value, with object keys separated by "." and array indexes in
+	// brackets, e.g. "db.hosts[1]". It is empty for the root value.
+	Path string
+	// Old is the old value, or nil if it was added.
+	Old any
+	// New is the new value, or nil if it was removed.
+	New any
+}
+
+// String returns the change in the form "path: old -> new", with the values in JSON.
+func (c Change) String() string {
+	old, _ := json.Marshal(c.Old)
+	new, _ := json.Marshal(c.New)
+	return fmt.Sprintf("%s: %s -> %s", c.Path, old, new)
+}
+
+// Diff returns the changes between the old and new configurations, in the order of their paths.
+// The configurations are compared in their JSON form, so only the exported fields are compared
+// and the values of a Change are those of encoding/json: maps, slices, strings, float64, and so on.
+// If a configuration cannot be marshalled, Diff reports a single change of the root value.
+func Diff[H any](old, new H) []Change {
+	o, err1 := jsonValue(old)
+	n, err2 := jsonValue(new)
+	if err1 != nil || err2 != nil {
+		return []Change{{Old: old, New: new}}
+	}
+	var changes []Change
+	diffValues(&changes, "", o, n)
+	return changes
+}
+
+// OnDiff registers f to be called with the changes each time a load swaps the hub, if there are any.
+// See OnChange and Diff.
+func (c *Config[H]) OnDiff(f func(changes []Change)) (cancel func()) {
+	return c.OnChange(func(old, new H) {
+		if changes := Diff(old, new); len(changes) > 0 {
+			f(changes)
+		}
+	})
+}
+
+func jsonValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var x any
+	err = json.Unmarshal(data, &x)
+	return x, err
+}
+
+func diffValues(changes *[]Change, path string, old, new any) {
+	switch o := old.(type) {
+	case map[string]any:
+		if n, ok := new.(map[string]any); ok {
+			keys := slices.Collect(maps.Keys(o))
+			for k := range n {
+				if _, ok := o[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				diffValues(changes, joinPath(path, k), o[k], n[k])
+			}
+			return
+		}
+	case []any:
+		if n, ok := new.([]any); ok {
+			for i := range max(len(o), len(n)) {
+				var ov, nv any
+				if i < len(o) {
+					ov = o[i]
+				}
+				if i < len(n) {
+					nv = n[i]
+				}
+				diffValues(changes, path+"["+strconv.Itoa(i)+"]", ov, nv)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, Change{Path: path, Old: old, New: new})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
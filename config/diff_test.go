@@ -0,0 +1,138 @@
+package config_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gopherd/core/encoding"
+	"github.com/gopherd/exp/config"
+)
+
+type diffDB struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type diffConfig struct {
+	Name   string            `json:"name"`
+	DB     diffDB            `json:"db"`
+	Hosts  []string          `json:"hosts"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Extra  *diffDB           `json:"extra,omitempty"`
+	secret string
+}
+
+func TestDiff(t *testing.T) {
+	base := diffConfig{
+		Name:   "a",
+		DB:     diffDB{Host: "h", Port: 1},
+		Hosts:  []string{"x", "y"},
+		Labels: map[string]string{"env": "dev"},
+	}
+	tests := []struct {
+		name   string
+		change func(*diffConfig)
+		want   []config.Change
+	}{
+		{"unchanged", func(c *diffConfig) {}, nil},
+		{"unexported field ignored", func(c *diffConfig) { c.secret = "s" }, nil},
+		{"changed field", func(c *diffConfig) { c.Name = "b" }, []config.Change{
+			{Path: "name", Old: "a", New: "b"},
+		}},
+		{"nested struct", func(c *diffConfig) { c.DB.Port = 2 }, []config.Change{
+			{Path: "db.port", Old: 1.0, New: 2.0},
+		}},
+		{"added field", func(c *diffConfig) { c.Extra = &diffDB{Host: "e"} }, []config.Change{
+			{Path: "extra", Old: nil, New: map[string]any{"host": "e", "port": 0.0}},
+		}},
+		{"removed field", func(c *diffConfig) { c.Labels = nil }, []config.Change{
+			{Path: "labels", Old: map[string]any{"env": "dev"}, New: nil},
+		}},
+		{"map key changed", func(c *diffConfig) { c.Labels = map[string]string{"env": "prod"} }, []config.Change{
+			{Path: "labels.env", Old: "dev", New: "prod"},
+		}},
+		{"map keys added and removed", func(c *diffConfig) { c.Labels = map[string]string{"zone": "z"} }, []config.Change{
+			{Path: "labels.env", Old: "dev", New: nil},
+			{Path: "labels.zone", Old: nil, New: "z"},
+		}},
+		{"slice element", func(c *diffConfig) { c.Hosts = []string{"x", "z"} }, []config.Change{
+			{Path: "hosts[1]", Old: "y", New: "z"},
+		}},
+		{"slice grown", func(c *diffConfig) { c.Hosts = append(c.Hosts, "z") }, []config.Change{
+			{Path: "hosts[2]", Old: nil, New: "z"},
+		}},
+		{"slice shrunk", func(c *diffConfig) { c.Hosts = c.Hosts[:1] }, []config.Change{
+			{Path: "hosts[1]", Old: "y", New: nil},
+		}},
+		{"slice to null", func(c *diffConfig) { c.Hosts = nil }, []config.Change{
+			{Path: "hosts", Old: []any{"x", "y"}, New: nil},
+		}},
+		{"sorted paths", func(c *diffConfig) { c.Name = "b"; c.DB.Host = "g" }, []config.Change{
+			{Path: "db.host", Old: "h", New: "g"},
+			{Path: "name", Old: "a", New: "b"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			new := base
+			new.Hosts = append([]string(nil), base.Hosts...)
+			tt.change(&new)
+			if got := config.Diff(base, new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiff_Root(t *testing.T) {
+	if got := config.Diff(1, 2); len(got) != 1 || got[0].Path != "" || got[0].String() != ": 1 -> 2" {
+		t.Errorf("Expected a change of the root value, got %v", got)
+	}
+	if got := config.Diff[any](func() {}, 1); len(got) != 1 || got[0].Path != "" {
+		t.Errorf("Expected a change of the root value for a value that cannot be marshalled, got %v", got)
+	}
+	if got := (config.Change{Path: "a.b", Old: "x", New: nil}).String(); got != `a.b: "x" -> null` {
+		t.Errorf("Expected a.b: \"x\" -> null, got %s", got)
+	}
+}
+
+// diffHub is a hub whose fields are compared by Diff.
+type diffHub struct {
+	App diffDB `json:"app"`
+}
+
+func (h *diffHub) Parse(data []byte, decoder encoding.Decoder) error {
+	return decoder(data, h)
+}
+
+func TestOnDiff(t *testing.T) {
+	c := config.NewConfig(func() *diffHub { return &diffHub{} })
+	var changes [][]config.Change
+	cancel := c.OnDiff(func(cs []config.Change) {
+		changes = append(changes, cs)
+	})
+	defer cancel()
+	var data string
+	options := config.Options{
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			return []byte(data), nil
+		},
+		Scopes: config.Scopes{"app"},
+	}
+	ctx := context.Background()
+	for _, d := range []string{`{"app":{"port":1}}`, `{"app":{"port":1}}`, `{"app":{"host":"h","port":2}}`} {
+		data = d
+		if _, err := c.Load(ctx, options); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first load changes the hub from nil, and the second one does not change it.
+	want := [][]config.Change{
+		{{Path: "", Old: nil, New: map[string]any{"app": map[string]any{"host": "", "port": 1.0}}}},
+		{{Path: "app.host", Old: "", New: "h"}, {Path: "app.port", Old: 1.0, New: 2.0}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %v, got %v", want, changes)
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gopherd/core/encoding"
+)
+
+// EnvOverlay overrides values of the loaded data with environment variables, so that deployments
+// can override a value without editing the configuration files.
+//
+// A variable named Prefix + path, where the segments of path are separated by Separator, overrides
+// the value at the path of the data, whose first segment is the scope. For example, with the prefix
+// "APP_", APP_DB__HOST overrides the value of db.host. Segments match keys case-insensitively and
+// ignoring "_" and "-", so APP_DB__MAX_CONNS also overrides db.maxConns. Missing keys are created.
+//
+// A value that overrides a string is used as a string; other values, including the values of missing
+// keys, are parsed as JSON if possible, so that numbers, booleans, and arrays can be overridden.
+type EnvOverlay struct {
+	// Prefix is the prefix of the environment variables, e.g. "APP_". The overlay is disabled if it is empty.
+	Prefix string
+	// Separator separates the segments of the path in a variable name (default is "__").
+	Separator string
+	// Environ returns the environment as "key=value" strings (default is os.Environ).
+	Environ func() []string
+}
+
+// apply applies the overlay to the data.
+func (e *EnvOverlay) apply(data []byte, enc encoding.Encoder, dec encoding.Decoder) ([]byte, error) {
+	if e.Prefix == "" {
+		return data, nil
+	}
+	sep := e.Separator
+	if sep == "" {
+		sep = "__"
+	}
+	var root map[string]any
+	for _, kv := range e.vars() {
+		key, value, _ := strings.Cut(kv, "=")
+		path := strings.Split(strings.ToLower(strings.TrimPrefix(key, e.Prefix)), sep)
+		if slices.Contains(path, "") {
+			continue
+		}
+		if root == nil {
+			if err := dec(data, &root); err != nil {
+				return nil, err
+			}
+			if root == nil {
+				root = make(map[string]any)
+			}
+		}
+		setPath(root, path, value)
+	}
+	if root == nil {
+		return data, nil
+	}
+	return enc(root)
+}
+
+// vars returns the environment variables with the prefix, as sorted "key=value" strings.
+func (e *EnvOverlay) vars() []string {
+	if e.Prefix == "" {
+		return nil
+	}
+	environ := e.Environ
+	if environ == nil {
+		environ = os.Environ
+	}
+	var vars []string
+	for _, kv := range environ() {
+		if key, _, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(key, e.Prefix) {
+			vars = append(vars, kv)
+		}
+	}
+	slices.Sort(vars)
+	return vars
+}
+
+// setPath sets the value at the path of m, creating missing objects.
+func setPath(m map[string]any, path []string, value string) {
+	for _, segment := range path[:len(path)-1] {
+		key := matchKey(m, segment)
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			m[key] = child
+		}
+		m = child
+	}
+	key := matchKey(m, path[len(path)-1])
+	if _, ok := m[key].(string); ok {
+		m[key] = value
+		return
+	}
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err == nil {
+		m[key] = v
+	} else {
+		m[key] = value
+	}
+}
+
+// matchKey returns the key of m that matches the segment, or the segment if there is none.
+func matchKey(m map[string]any, segment string) string {
+	if _, ok := m[segment]; ok {
+		return segment
+	}
+	normalized := normalizeKey(segment)
+	for key := range m {
+		if normalizeKey(key) == normalized {
+			return key
+		}
+	}
+	return segment
+}
+
+var keySeparators = strings.NewReplacer("_", "", "-", "")
+
+func normalizeKey(key string) string {
+	return strings.ToLower(keySeparators.Replace(key))
+}
@@ -0,0 +1,79 @@
+package config_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gopherd/exp/config"
+)
+
+func TestEnvOverlay(t *testing.T) {
+	const data = `{"db":{"host":"localhost","port":5432,"maxConns":10,"tls":false,"tags":["a"],"name":"123"},"app":{"debug":false}}`
+	tests := []struct {
+		name      string
+		separator string
+		env       []string
+		path      []string
+		want      any
+	}{
+		{"nested string", "", []string{"APP_DB__HOST=db.internal"}, []string{"db", "host"}, "db.internal"},
+		{"number", "", []string{"APP_DB__PORT=6543"}, []string{"db", "port"}, 6543.0},
+		{"bool", "", []string{"APP_APP__DEBUG=true"}, []string{"app", "debug"}, true},
+		{"array", "", []string{`APP_DB__TAGS=["b","c"]`}, []string{"db", "tags"}, []any{"b", "c"}},
+		{"string kept as string", "", []string{"APP_DB__NAME=456"}, []string{"db", "name"}, "456"},
+		{"invalid JSON for a number", "", []string{"APP_DB__PORT=auto"}, []string{"db", "port"}, "auto"},
+		{"case and separators ignored", "", []string{"APP_DB__MAX_CONNS=20"}, []string{"db", "maxConns"}, 20.0},
+		{"missing key", "", []string{"APP_DB__USER=admin"}, []string{"db", "user"}, "admin"},
+		{"missing object", "", []string{"APP_CACHE__REDIS__ADDR=:6379"}, []string{"cache", "redis", "addr"}, ":6379"},
+		{"missing key parsed", "", []string{"APP_DB__RETRIES=3"}, []string{"db", "retries"}, 3.0},
+		{"missing key as array", "", []string{`APP_DB__TAGS=["a"]`}, []string{"db", "tags"}, []any{"a"}},
+		{"other prefix ignored", "", []string{"OTHER_DB__HOST=x"}, []string{"db", "host"}, "localhost"},
+		{"empty segment ignored", "", []string{"APP_DB____HOST=x"}, []string{"db", "host"}, "localhost"},
+		{"custom separator", ".", []string{"APP_db.host=x"}, []string{"db", "host"}, "x"},
+		{"last variable wins", "", []string{"APP_DB__HOST=a", "APP_DB__host=b"}, []string{"db", "host"}, "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestConfig()
+			_, err := c.Load(context.Background(), config.Options{
+				Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+					return []byte(data), nil
+				},
+				Scopes: config.Scopes{"db", "app"},
+				Env: &config.EnvOverlay{
+					Prefix:    "APP_",
+					Separator: tt.separator,
+					Environ:   func() []string { return tt.env },
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Latest().value(tt.path...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v (%T), got %v (%T)", tt.want, tt.want, got, got)
+			}
+		})
+	}
+}
+
+func TestEnvOverlay_YAML(t *testing.T) {
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		ContentType: config.ContentTypeYAML,
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			return []byte("db:\n  port: 5432\n"), nil
+		},
+		Scopes: config.Scopes{"db"},
+		Env: &config.EnvOverlay{
+			Prefix:  "APP_",
+			Environ: func() []string { return []string{"APP_DB__PORT=6543", "PATH=/bin"} },
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Latest().value("db", "port"); got != 6543 {
+		t.Errorf("Expected 6543, got %v (%T)", got, got)
+	}
+}
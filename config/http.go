@@ -0,0 +1,95 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// NewTLSConfig returns a TLS configuration for HTTPS sources that trusts the CA certificates in the
+// PEM file caFile, in addition to the system ones, and presents the client certificate and key in
+// the PEM files certFile and keyFile. Empty file names are ignored.
+func NewTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no CA certificate found in " + caFile)
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// httpClientKey is the set of options an HTTP client is built from.
+type httpClientKey struct {
+	base      *http.Client
+	timeout   time.Duration
+	proxy     string
+	tlsConfig *tls.Config
+}
+
+// cachedHTTPClient is an HTTP client built from options, cached so that its connections are reused.
+type cachedHTTPClient struct {
+	key    httpClientKey
+	client *http.Client
+}
+
+// httpClient returns the HTTP client of remote sources for the options.
+func (c *Config[H]) httpClient(options Options) (*http.Client, error) {
+	key := httpClientKey{options.HTTPClient, options.Timeout, options.Proxy, options.TLSConfig}
+	if key.base == nil {
+		key.base = http.DefaultClient
+	}
+	if key.timeout == 0 && key.proxy == "" && key.tlsConfig == nil {
+		return key.base, nil
+	}
+	if cached := c.client.Load(); cached != nil && cached.key == key {
+		return cached.client, nil
+	}
+	client := *key.base
+	if key.timeout > 0 {
+		client.Timeout = key.timeout
+	}
+	if key.proxy != "" || key.tlsConfig != nil {
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			if client.Transport != nil {
+				return nil, errors.New("proxy and TLS options require the transport of the HTTP client to be an *http.Transport")
+			}
+			transport = http.DefaultTransport.(*http.Transport)
+		}
+		transport = transport.Clone()
+		if key.proxy != "" {
+			u, err := url.Parse(key.proxy)
+			if err != nil {
+				return nil, err
+			}
+			transport.Proxy = http.ProxyURL(u)
+		}
+		if key.tlsConfig != nil {
+			transport.TLSClientConfig = key.tlsConfig
+		}
+		client.Transport = transport
+	}
+	c.client.Store(&cachedHTTPClient{key: key, client: &client})
+	return &client, nil
+}
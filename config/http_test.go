@@ -0,0 +1,314 @@
+package config_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/config"
+)
+
+// writePEM writes the PEM block of the given type and bytes to the file name in dir, and returns its path.
+func writePEM(t *testing.T, dir, name, typ string, b []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newClientCert generates a self-signed client certificate, writes it and its key to PEM files in
+// dir, and returns the certificate and the paths of the files.
+func newClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, writePEM(t, dir, "client.pem", "CERTIFICATE", der), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+// configHandler responds with the configuration of the app scope.
+var configHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"app":{"port":1}}`))
+})
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewTLSServer(configHandler)
+	defer server.Close()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	_, certFile, keyFile := newClientCert(t, dir)
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                      string
+		caFile, certFile, keyFile string
+		err                       string // substring of the expected error, or empty for success
+		rootCAs                   bool
+		certificates              int
+	}{
+		{"empty", "", "", "", "", false, 0},
+		{"CA", caFile, "", "", "", true, 0},
+		{"client certificate", "", certFile, keyFile, "", false, 1},
+		{"all", caFile, certFile, keyFile, "", true, 1},
+		{"missing CA file", filepath.Join(dir, "missing.pem"), "", "", "no such file", false, 0},
+		{"no CA certificate", invalidFile, "", "", "no CA certificate", false, 0},
+		{"missing key", "", certFile, "", "no such file", false, 0},
+		{"mismatched key", "", certFile, caFile, "PEM", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := config.NewTLSConfig(tt.caFile, tt.certFile, tt.keyFile)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (tlsConfig.RootCAs != nil) != tt.rootCAs {
+				t.Errorf("Expected root CAs %v, got %v", tt.rootCAs, tlsConfig.RootCAs != nil)
+			}
+			if n := len(tlsConfig.Certificates); n != tt.certificates {
+				t.Errorf("Expected %d certificates, got %d", tt.certificates, n)
+			}
+			if tlsConfig.MinVersion != tls.VersionTLS12 {
+				t.Errorf("Expected TLS 1.2 at least, got %x", tlsConfig.MinVersion)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_TLS(t *testing.T) {
+	dir := t.TempDir()
+	cert, certFile, keyFile := newClientCert(t, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server := httptest.NewUnstartedServer(configHandler)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	load := func(tlsConfig *tls.Config) error {
+		c, _ := newTestConfig()
+		_, err := c.Load(context.Background(), config.Options{Source: server.URL, Scopes: config.Scopes{"app"}, TLSConfig: tlsConfig})
+		return err
+	}
+	if err := load(nil); err == nil {
+		t.Error("Expected an error for an untrusted server certificate")
+	}
+	caOnly, err := config.NewTLSConfig(caFile, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := load(caOnly); err == nil {
+		t.Error("Expected an error without a client certificate")
+	}
+	full, err := config.NewTLSConfig(caFile, certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := load(full); err != nil {
+		t.Errorf("Expected success with the CA and the client certificate, got %v", err)
+	}
+}
+
+func TestHTTPClient_Timeout(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		configHandler(w, r)
+	}))
+	defer server.Close()
+
+	// The first attempt times out, and the second one succeeds.
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Source:  server.URL,
+		Scopes:  config.Scopes{"app"},
+		Timeout: 50 * time.Millisecond,
+		Retry:   &config.Retry{MaxAttempts: 2, Backoff: time.Millisecond},
+	})
+	if err != nil || requests.Load() != 2 {
+		t.Errorf("Expected success after 2 requests, got %v after %d requests", err, requests.Load())
+	}
+
+	// Without retries, the timeout is reported.
+	requests.Store(0)
+	_, err = c.Load(context.Background(), config.Options{Source: server.URL, Scopes: config.Scopes{"app"}, Timeout: 50 * time.Millisecond})
+	if err == nil {
+		t.Error("Expected a timeout error")
+	}
+}
+
+func TestHTTPClient_Proxy(t *testing.T) {
+	var host atomic.Pointer[string]
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.Store(&r.Host)
+		configHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	base := &http.Transport{}
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Source:     "http://config.invalid/config",
+		Scopes:     config.Scopes{"app"},
+		HTTPClient: &http.Client{Transport: base},
+		Proxy:      proxy.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := host.Load(); h == nil || *h != "config.invalid" {
+		t.Errorf("Expected the request to be sent through the proxy, got host %v", h)
+	}
+	if base.Proxy != nil {
+		t.Error("Expected the transport of the HTTP client to be cloned rather than modified")
+	}
+}
+
+// roundTripFunc is an http.RoundTripper that is not an *http.Transport.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestHTTPClient_Transport(t *testing.T) {
+	server := httptest.NewServer(configHandler)
+	defer server.Close()
+	client := &http.Client{Transport: roundTripFunc(http.DefaultTransport.RoundTrip)}
+
+	c, _ := newTestConfig()
+	options := config.Options{Source: server.URL, Scopes: config.Scopes{"app"}, HTTPClient: client, Timeout: time.Second}
+	if _, err := c.Load(context.Background(), options); err != nil {
+		t.Errorf("Expected a timeout to be supported by any transport, got %v", err)
+	}
+	options.Proxy = "http://proxy.invalid"
+	if _, err := c.Load(context.Background(), options); err == nil || !strings.Contains(err.Error(), "*http.Transport") {
+		t.Errorf("Expected an error for a proxy with a custom transport, got %v", err)
+	}
+	options.Proxy, options.TLSConfig = "", &tls.Config{}
+	if _, err := c.Load(context.Background(), options); err == nil || !strings.Contains(err.Error(), "*http.Transport") {
+		t.Errorf("Expected an error for a TLS configuration with a custom transport, got %v", err)
+	}
+}
+
+func TestHTTPClient_Cache(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(configHandler)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	c, _ := newTestConfig()
+	options := config.Options{Source: server.URL, Scopes: config.Scopes{"app"}, TLSConfig: tlsConfig}
+	for range 3 {
+		if _, err := c.Load(context.Background(), options); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := conns.Load(); n != 1 {
+		t.Errorf("Expected the client to be reused with its connection, got %d connections", n)
+	}
+
+	// Other options build another client.
+	options.Timeout = time.Second
+	if _, err := c.Load(context.Background(), options); err != nil {
+		t.Fatal(err)
+	}
+	if n := conns.Load(); n != 2 {
+		t.Errorf("Expected a new client for other options, got %d connections", n)
+	}
+}
+
+func TestConsulWatch_Timeout(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"cfg/app.json": `{"name":"a"}`})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	// The blocking queries take 200ms, longer than the timeout, which must be extended by their wait time.
+	c, _ := newTestConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	options := config.Options{Source: consulSource(server, "cfg"), Scopes: config.Scopes{"app"}, Timeout: 50 * time.Millisecond}
+	if _, err := c.Load(ctx, options); err != nil {
+		t.Fatal(err)
+	}
+	handle, err := c.Watch(ctx, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if s := c.LastLoad(); s.Err != nil {
+		t.Errorf("Expected the blocking query not to time out, got %v", s.Err)
+	}
+
+	fake.set("cfg/app.json", `{"name":"b"}`)
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Latest().value("app", "name") != "b" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if v := c.Latest().value("app", "name"); v != "b" {
+		t.Errorf("Expected app.name b after the change, got %v", v)
+	}
+	cancel()
+	handle.Join(context.Background())
+}
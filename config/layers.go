@@ -0,0 +1,188 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Source is a layer of configuration data loaded with Options.Sources.
+type Source struct {
+	// URL is the location of the data, in one of the formats of Options.Source.
+	URL string
+
+	// ContentType is the content type of the data or empty (default is the ContentType of the Options).
+	ContentType ContentType
+
+	// Fetch is the function to fetch data or nil. If it is not nil, the URL is ignored.
+	Fetch func(contentType ContentType, scopes Scopes) ([]byte, error)
+
+	// Namer is the function to name the scope or nil (default is the Namer of the Options).
+	Namer func(scope, ext string) string
+}
+
+// loadLayers loads the data from each of the sources and merges them in order: values of later
+// sources override values of earlier ones, and objects are merged recursively. It skips parsing if
+// the merged data, and the environment variables of the overlay, have not changed since the last
+// load. Loads are serialized, so that concurrent loads do not swap in older data.
+func (c *Config[H]) loadLayers(ctx context.Context, options Options) (bool, error) {
+	_, enc, _, err := options.ContentType.Parse()
+	if err != nil {
+		return false, err
+	}
+	client, err := c.httpClient(options)
+	if err != nil {
+		return false, err
+	}
+	c.layersMu.Lock()
+	defer c.layersMu.Unlock()
+	merged := make(map[string]any)
+	for i, source := range options.Sources {
+		layer, err := fetchLayer(ctx, client, source, options)
+		if err != nil {
+			return false, fmt.Errorf("source %d: %w", i, err)
+		}
+		mergeMaps(merged, layer)
+	}
+	data := make(map[string]any, len(options.Scopes))
+	for _, scope := range options.Scopes {
+		v, ok := merged[scope]
+		if !ok {
+			return false, fmt.Errorf("scope %q: %w", scope, ErrNotFound)
+		}
+		data[scope] = v
+	}
+	content, err := enc(data)
+	if err != nil {
+		return false, err
+	}
+	h := sha256.New()
+	h.Write(content)
+	if options.Env != nil {
+		for _, kv := range options.Env.vars() {
+			fmt.Fprintf(h, "\x00%s", kv)
+		}
+	}
+	var digest [sha256.Size]byte
+	h.Sum(digest[:0])
+	if c.layersDigest != nil && *c.layersDigest == digest {
+		return false, nil
+	}
+	if err := c.parse(content, options); err != nil {
+		return false, err
+	}
+	c.layersDigest = &digest
+	return true, nil
+}
+
+// fetchLayer fetches and decodes the data of the scopes from the source. Scopes that the
+// source does not have are omitted.
+func fetchLayer(ctx context.Context, client *http.Client, source Source, options Options) (map[string]any, error) {
+	if source.ContentType == "" {
+		source.ContentType = options.ContentType
+	}
+	if source.Namer == nil {
+		source.Namer = options.Namer
+	}
+	ext, _, dec, err := source.ContentType.Parse()
+	if err != nil {
+		return nil, err
+	}
+	layer := make(map[string]any)
+	decode := func(data []byte) error {
+		return dec(data, &layer)
+	}
+	switch {
+	case source.Fetch != nil:
+		var data []byte
+		err := options.Retry.do(ctx, options.attempts, func() (err error) {
+			data, err = source.Fetch(source.ContentType, options.Scopes)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return layer, decode(data)
+	case strings.HasPrefix(source.URL, "http://") || strings.HasPrefix(source.URL, "https://"):
+		var data []byte
+		err := options.Retry.do(ctx, options.attempts, func() (err error) {
+			_, data, err = fetch(ctx, client, httpValidators{}, source.URL, string(source.ContentType), options.Scopes)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return layer, decode(data)
+	}
+
+	scoped := Options{Source: source.URL, ContentType: source.ContentType, Scopes: options.Scopes, Namer: source.Namer}
+	contents := make(map[string][]byte)
+	if strings.HasPrefix(source.URL, "consul://") {
+		s, err := parseConsulSource(source.URL, client)
+		if err != nil {
+			return nil, err
+		}
+		var pairs []consulPair
+		err = options.Retry.do(ctx, options.attempts, func() (err error) {
+			pairs, _, err = s.list(ctx, 0, 0)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string][]byte, len(pairs))
+		for _, p := range pairs {
+			values[p.Key] = p.Value
+		}
+		for _, scope := range options.Scopes {
+			if content, ok := values[s.key(scopeName(scoped, scope, ext))]; ok {
+				contents[scope] = content
+			}
+		}
+	} else {
+		files, err := scopeFiles(scoped)
+		if err != nil {
+			return nil, err
+		}
+		for scope, path := range files {
+			content, err := os.ReadFile(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			contents[scope] = content
+		}
+	}
+	for _, scope := range options.Scopes {
+		content, ok := contents[scope]
+		if !ok {
+			continue
+		}
+		var v any
+		if err := dec(content, &v); err != nil {
+			return nil, fmt.Errorf("scope %q: %w", scope, err)
+		}
+		layer[scope] = v
+	}
+	return layer, nil
+}
+
+// mergeMaps merges src into dst recursively: values of src override those of dst, except that
+// objects present in both are merged.
+func mergeMaps(dst, src map[string]any) {
+	for k, v := range src {
+		if s, ok := v.(map[string]any); ok {
+			if d, ok := dst[k].(map[string]any); ok {
+				mergeMaps(d, s)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
@@ -0,0 +1,141 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gopherd/exp/config"
+)
+
+// layer returns a Source that fetches the given data.
+func layer(data string) config.Source {
+	return config.Source{Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+		return []byte(data), nil
+	}}
+}
+
+func TestSources_Merge(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers []string
+		path   []string
+		want   any
+	}{
+		{"later value wins", []string{`{"app":{"port":1}}`, `{"app":{"port":2}}`}, []string{"app", "port"}, 2.0},
+		{"earlier value kept", []string{`{"app":{"host":"a","port":1}}`, `{"app":{"port":2}}`}, []string{"app", "host"}, "a"},
+		{"three layers", []string{`{"app":{"port":1}}`, `{"app":{"port":2}}`, `{"app":{"port":3}}`}, []string{"app", "port"}, 3.0},
+		{"nested objects merged", []string{`{"app":{"db":{"host":"a","port":1}}}`, `{"app":{"db":{"port":2}}}`}, []string{"app", "db"}, map[string]any{"host": "a", "port": 2.0}},
+		{"slices replaced", []string{`{"app":{"tags":["a","b","c"]}}`, `{"app":{"tags":["d"]}}`}, []string{"app", "tags"}, []any{"d"}},
+		{"object replaced by scalar", []string{`{"app":{"db":{"host":"a"}}}`, `{"app":{"db":"none"}}`}, []string{"app", "db"}, "none"},
+		{"scalar replaced by object", []string{`{"app":{"db":"none"}}`, `{"app":{"db":{"host":"a"}}}`}, []string{"app", "db"}, map[string]any{"host": "a"}},
+		{"null overrides", []string{`{"app":{"port":1}}`, `{"app":{"port":null}}`}, []string{"app", "port"}, nil},
+		{"scope missing from a layer", []string{`{"app":{"port":1}}`, `{"db":{"port":2}}`}, []string{"app", "port"}, 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sources []config.Source
+			for _, data := range tt.layers {
+				sources = append(sources, layer(data))
+			}
+			c, _ := newTestConfig()
+			_, err := c.Load(context.Background(), config.Options{Sources: sources, Scopes: config.Scopes{"app"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Latest().value(tt.path...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSources_Kinds(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("name: base\nport: 1\ndb:\n  host: a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"app":{"port":2,"db":{"port":5432}}}`))
+	}))
+	defer server.Close()
+
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Sources: []config.Source{
+			{URL: "file://" + dir, ContentType: config.ContentTypeYAML},
+			{URL: server.URL},
+			layer(`{"app":{"name":"override"}}`),
+		},
+		Scopes: config.Scopes{"app"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"name": "override", "port": 2.0, "db": map[string]any{"host": "a", "port": 5432.0}}
+	if got := c.Latest().value("app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSources_MissingScope(t *testing.T) {
+	c, _ := newTestConfig()
+	changed, err := c.Load(context.Background(), config.Options{
+		Sources: []config.Source{layer(`{"app":{}}`), {URL: t.TempDir()}},
+		Scopes:  config.Scopes{"app", "db"},
+	})
+	if !errors.Is(err, config.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a scope missing from all sources, got %v", err)
+	}
+	if changed {
+		t.Error("Expected a failed load not to report a change")
+	}
+}
+
+func TestSources_Unchanged(t *testing.T) {
+	base, override := `{"app":{"port":1}}`, `{"app":{}}`
+	var env []string
+	c, parses := newTestConfig()
+	options := config.Options{
+		Sources: []config.Source{
+			{Fetch: func(config.ContentType, config.Scopes) ([]byte, error) { return []byte(base), nil }},
+			{Fetch: func(config.ContentType, config.Scopes) ([]byte, error) { return []byte(override), nil }},
+		},
+		Scopes: config.Scopes{"app"},
+		Env:    &config.EnvOverlay{Prefix: "APP_", Environ: func() []string { return env }},
+	}
+	tests := []struct {
+		name    string
+		update  func()
+		changed bool
+	}{
+		{"first load", func() {}, true},
+		{"unchanged", func() {}, false},
+		{"layer changed", func() { override = `{"app":{"port":2}}` }, true},
+		{"merged data unchanged", func() { base = `{"app":{"port":3}}` }, false},
+		{"environment changed", func() { env = []string{"APP_APP__PORT=4"} }, true},
+		{"unchanged again", func() {}, false},
+	}
+	want := 0
+	for _, tt := range tests {
+		tt.update()
+		changed, err := c.Load(context.Background(), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed != tt.changed {
+			t.Errorf("%s: expected changed %v, got %v", tt.name, tt.changed, changed)
+		}
+		if changed {
+			want++
+		}
+		if *parses != want {
+			t.Errorf("%s: expected %d parses, got %d", tt.name, want, *parses)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Default values of Retry.
+const (
+	DefaultRetryBackoff    = 100 * time.Millisecond
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryableStatus is the default list of HTTP status codes that are retried.
+var DefaultRetryableStatus = []int{408, 429, 500, 502, 503, 504}
+
+// Retry is the retry policy of remote fetches: HTTP and Consul sources, and custom Fetch functions.
+// Network errors, including the timeouts of single requests, and errors of Fetch functions are
+// retried; HTTP responses are retried if their status code is retryable.
+type Retry struct {
+	// MaxAttempts is the maximum number of attempts of a fetch, including the first one.
+	// A value of 0 or 1 disables retries.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, doubled after each retry (default is DefaultRetryBackoff).
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries (default is DefaultRetryMaxBackoff).
+	MaxBackoff time.Duration
+
+	// RetryableStatus is the list of HTTP status codes to retry (default is DefaultRetryableStatus).
+	RetryableStatus []int
+}
+
+// StatusError is the error of an HTTP response with an unexpected status code.
+type StatusError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// Status is the status of the response, e.g. "503 Service Unavailable".
+	Status string
+}
+
+// Error returns the error message.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s", e.Status)
+}
+
+// LoadStatus is the status of the last load of a Config.
+type LoadStatus struct {
+	// Time is the time the load completed.
+	Time time.Time
+	// Attempts is the number of attempts of the remote fetches of the load.
+	Attempts int
+	// Err is the error of the load, or nil if it succeeded.
+	Err error
+}
+
+// do calls f until it succeeds, fails with an error that is not retried, the maximum number of
+// attempts is reached, or the context is canceled, and adds the number of attempts to the counter
+// if it is not nil. It returns the error of the last attempt.
+func (r *Retry) do(ctx context.Context, attempts *int, f func() error) error {
+	maxAttempts, delay, maxDelay := 1, DefaultRetryBackoff, DefaultRetryMaxBackoff
+	if r != nil {
+		maxAttempts = max(r.MaxAttempts, 1)
+		if r.Backoff > 0 {
+			delay = r.Backoff
+		}
+		if r.MaxBackoff > 0 {
+			maxDelay = r.MaxBackoff
+		}
+	}
+	for n := 1; ; n++ {
+		if attempts != nil {
+			*attempts++
+		}
+		err := f()
+		if err == nil || n >= maxAttempts || ctx.Err() != nil || !r.retryable(err) {
+			return err
+		}
+		if sleep(ctx, delay); ctx.Err() != nil {
+			return err
+		}
+		delay = min(delay*2, maxDelay)
+	}
+}
+
+// retryable reports whether the error of a fetch is retried.
+func (r *Retry) retryable(err error) bool {
+	if errors.Is(err, errNotModified) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		codes := r.RetryableStatus
+		if codes == nil {
+			codes = DefaultRetryableStatus
+		}
+		return slices.Contains(codes, se.StatusCode)
+	}
+	return true
+}
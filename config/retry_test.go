@@ -0,0 +1,172 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/config"
+)
+
+// failingServer returns a server that responds with status to the first n requests, and then
+// with the configuration, and the counter of its requests.
+func failingServer(t *testing.T, n int32, status int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= n {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"app":{"port":1}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		status   int
+		retry    *config.Retry
+		attempts int
+		err      int // status code of the expected error, or 0 for success
+	}{
+		{"no retry", 1, http.StatusServiceUnavailable, nil, 1, http.StatusServiceUnavailable},
+		{"single attempt", 1, http.StatusServiceUnavailable, &config.Retry{MaxAttempts: 1}, 1, http.StatusServiceUnavailable},
+		{"recovered", 2, http.StatusServiceUnavailable, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond}, 3, 0},
+		{"exhausted", 5, http.StatusBadGateway, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond}, 3, http.StatusBadGateway},
+		{"too many requests", 1, http.StatusTooManyRequests, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond}, 2, 0},
+		{"not retryable", 1, http.StatusNotFound, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond}, 1, http.StatusNotFound},
+		{"custom retryable", 1, http.StatusNotFound, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond, RetryableStatus: []int{http.StatusNotFound}}, 2, 0},
+		{"custom not retryable", 1, http.StatusServiceUnavailable, &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond, RetryableStatus: []int{http.StatusNotFound}}, 1, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := failingServer(t, tt.failures, tt.status)
+			c, _ := newTestConfig()
+			_, err := c.Load(context.Background(), config.Options{Source: server.URL, Scopes: config.Scopes{"app"}, Retry: tt.retry})
+			var se *config.StatusError
+			if tt.err == 0 && err != nil {
+				t.Errorf("Expected success, got %v", err)
+			} else if tt.err != 0 && (!errors.As(err, &se) || se.StatusCode != tt.err) {
+				t.Errorf("Expected status error %d, got %v", tt.err, err)
+			}
+			if n := int(requests.Load()); n != tt.attempts {
+				t.Errorf("Expected %d requests, got %d", tt.attempts, n)
+			}
+			if s := c.LastLoad(); s == nil || s.Attempts != tt.attempts || !errors.Is(s.Err, err) {
+				t.Errorf("Expected %d attempts in the last load status, got %+v", tt.attempts, s)
+			}
+		})
+	}
+}
+
+func TestRetry_Backoff(t *testing.T) {
+	server, _ := failingServer(t, 4, http.StatusServiceUnavailable)
+	c, _ := newTestConfig()
+	start := time.Now()
+	_, err := c.Load(context.Background(), config.Options{
+		Source: server.URL,
+		Scopes: config.Scopes{"app"},
+		Retry:  &config.Retry{MaxAttempts: 5, Backoff: 20 * time.Millisecond, MaxBackoff: 30 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The delays are 20, 30, 30 and 30ms: without MaxBackoff, they would be 20, 40, 80 and 160ms.
+	if d := time.Since(start); d < 110*time.Millisecond || d >= 300*time.Millisecond {
+		t.Errorf("Expected the retries to take from 110ms to 300ms, got %v", d)
+	}
+}
+
+func TestRetry_Cancel(t *testing.T) {
+	server, requests := failingServer(t, 10, http.StatusServiceUnavailable)
+	c, _ := newTestConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := c.Load(ctx, config.Options{
+		Source: server.URL,
+		Scopes: config.Scopes{"app"},
+		Retry:  &config.Retry{MaxAttempts: 10, Backoff: time.Hour},
+	})
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected the load to stop when the context is canceled, took %v", d)
+	}
+	var se *config.StatusError
+	if !errors.As(err, &se) {
+		t.Errorf("Expected the error of the last attempt, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+	if s := c.LastLoad(); s.Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", s.Attempts)
+	}
+}
+
+func TestRetry_Fetch(t *testing.T) {
+	var calls int
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			if calls++; calls < 3 {
+				return nil, errors.New("unavailable")
+			}
+			return []byte(`{"app":{}}`), nil
+		},
+		Scopes: config.Scopes{"app"},
+		Retry:  &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond},
+	})
+	if err != nil || calls != 3 || c.LastLoad().Attempts != 3 {
+		t.Errorf("Expected success after 3 calls, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetry_Consul(t *testing.T) {
+	fake := newFakeConsul(map[string]string{"cfg/app.json": `{"port":1}`})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Source: "consul://" + strings.TrimPrefix(server.URL, "http://") + "/cfg",
+		Scopes: config.Scopes{"app"},
+		Retry:  &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond},
+	})
+	if err != nil || requests.Load() != 3 || c.LastLoad().Attempts != 3 {
+		t.Errorf("Expected success after 3 requests, got %v after %d requests", err, requests.Load())
+	}
+}
+
+func TestRetry_Timeout(t *testing.T) {
+	var calls int
+	c, _ := newTestConfig()
+	_, err := c.Load(context.Background(), config.Options{
+		Fetch: func(config.ContentType, config.Scopes) ([]byte, error) {
+			if calls++; calls == 1 {
+				return nil, fmt.Errorf("fetch: %w", context.DeadlineExceeded)
+			}
+			return []byte(`{"app":{}}`), nil
+		},
+		Scopes: config.Scopes{"app"},
+		Retry:  &config.Retry{MaxAttempts: 3, Backoff: time.Millisecond},
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("Expected success after 2 calls, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gopherd/exp/spawn"
+)
+
+// WatchDelay is the minimum interval between two reloads of a watched configuration. File events
+// within the interval are coalesced into one more reload at its end, so that an editor or a
+// deployment tool writing several files does not trigger a reload for each event.
+const WatchDelay = 100 * time.Millisecond
+
+// ErrNotWatchable is the error that the source of the configuration cannot be watched.
+var ErrNotWatchable = errors.New("source cannot be watched")
+
+// Watch watches the files of a file source with fsnotify, or the keys of a Consul source with
+// blocking queries, and reloads the configuration with the given options when they change, until
+// the context is canceled. Failed reloads are logged. It returns ErrNotWatchable for HTTP sources,
+// custom Fetch functions, and layered Sources.
+//
+// The directories of the files are watched rather than the files themselves, so that files replaced
+// by renaming, as most editors and deployment tools do, are still watched. Files that are symbolic
+// links are reloaded when their targets change, e.g. when Kubernetes swaps the "..data" link of a
+// mounted ConfigMap, even though no event names the files.
+func (c *Config[H]) Watch(ctx context.Context, options Options) (spawn.Handle, error) {
+	if len(options.Sources) > 0 {
+		return nil, ErrNotWatchable
+	}
+	if options.Fetch == nil && strings.HasPrefix(options.Source, "consul://") {
+		options.Scopes = options.Scopes.Compact()
+		return c.watchConsul(ctx, options)
+	}
+	if options.Fetch != nil || strings.HasPrefix(options.Source, "http://") || strings.HasPrefix(options.Source, "https://") {
+		return nil, ErrNotWatchable
+	}
+	options.Scopes = options.Scopes.Compact()
+	files, err := scopeFiles(options)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(files))
+	for _, path := range files {
+		targets[path], _ = filepath.EvalSymlinks(path)
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+			return nil, err
+		}
+	}
+
+	reload, throttle := spawn.Throttle(ctx, WatchDelay, func(ctx context.Context) {
+		if _, err := c.Load(ctx, options); err != nil {
+			slog.Error("failed to reload configuration", "error", err)
+		}
+	}, spawn.WithTrailing())
+	handle := spawn.Chan2(ctx, watcher.Events, func(ctx context.Context, event fsnotify.Event) {
+		if event.Op == fsnotify.Chmod {
+			return
+		}
+		_, watched := targets[filepath.Clean(event.Name)]
+		if retarget(targets) || watched {
+			reload()
+		}
+	}, watcher.Errors, func(ctx context.Context, err error) {
+		slog.Error("failed to watch configuration", "error", err)
+	}, spawn.WithExitOnClosed())
+	go func() {
+		<-handle.Done()
+		watcher.Close()
+		throttle.Cancel()
+	}()
+	return handle, nil
+}
+
+// retarget resolves the symbolic links of the watched files, and reports whether the target of any
+// of them has changed.
+func retarget(targets map[string]string) bool {
+	changed := false
+	for path, target := range targets {
+		if t, _ := filepath.EvalSymlinks(path); t != target {
+			targets[path] = t
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/config"
+)
+
+// watchDir loads the app scope of the directory, watches it until the end of the test, and returns
+// the configuration.
+func watchDir(t *testing.T, dir string) *config.Config[*testHub] {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c, _ := newTestConfig()
+	options := config.Options{Source: "file://" + dir, Scopes: config.Scopes{"app"}}
+	if _, err := c.Load(ctx, options); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Watch(ctx, options); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// waitPort waits until the port of the app scope of the latest configuration is want.
+func waitPort(t *testing.T, c *config.Config[*testHub], want float64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Latest().value("app", "port") != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected port %v, got %v", want, c.Latest().value("app", "port"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatch_Write(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.json"), `{"port":1}`)
+	c := watchDir(t, dir)
+	waitPort(t, c, 1)
+
+	writeFile(t, filepath.Join(dir, "app.json"), `{"port":2}`)
+	waitPort(t, c, 2)
+}
+
+func TestWatch_Rename(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.json"), `{"port":1}`)
+	c := watchDir(t, dir)
+
+	writeFile(t, filepath.Join(dir, "app.json.tmp"), `{"port":2}`)
+	if err := os.Rename(filepath.Join(dir, "app.json.tmp"), filepath.Join(dir, "app.json")); err != nil {
+		t.Fatal(err)
+	}
+	waitPort(t, c, 2)
+
+	// The replaced file is still watched.
+	writeFile(t, filepath.Join(dir, "app.json"), `{"port":3}`)
+	waitPort(t, c, 3)
+}
+
+func TestWatch_Symlink(t *testing.T) {
+	// The layout of a ConfigMap mounted by Kubernetes: app.json -> ..data/app.json, and
+	// ..data -> a versioned directory, which is swapped by renaming a new link over ..data.
+	dir := t.TempDir()
+	version := func(name, data string) {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(dir, name, "app.json"), data)
+		if err := os.Symlink(name, filepath.Join(dir, "..data_tmp")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version("..v1", `{"port":1}`)
+	if err := os.Symlink(filepath.Join("..data", "app.json"), filepath.Join(dir, "app.json")); err != nil {
+		t.Fatal(err)
+	}
+	c := watchDir(t, dir)
+	waitPort(t, c, 1)
+
+	version("..v2", `{"port":2}`)
+	if err := os.RemoveAll(filepath.Join(dir, "..v1")); err != nil {
+		t.Fatal(err)
+	}
+	waitPort(t, c, 2)
+
+	version("..v3", `{"port":3}`)
+	waitPort(t, c, 3)
+}
+
+func TestWatch_NotWatchable(t *testing.T) {
+	c, _ := newTestConfig()
+	_, err := c.Watch(context.Background(), config.Options{Source: "http://localhost/config", Scopes: config.Scopes{"app"}})
+	if !errors.Is(err, config.ErrNotWatchable) {
+		t.Errorf("Expected ErrNotWatchable, got %v", err)
+	}
+}
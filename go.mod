@@ -1,9 +1,12 @@
 module github.com/gopherd/exp
 
-go 1.21
+go 1.23
 
 require (
 	github.com/BurntSushi/toml v1.4.0
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/gopherd/core v0.0.0-20241029035757-89aa834201f1
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sys v0.13.0 // indirect
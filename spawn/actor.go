@@ -0,0 +1,92 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultMailbox is the default mailbox size of Actor.
+const DefaultMailbox = 64
+
+// ErrMailboxFull is returned by Mailbox.TrySend when the mailbox is full.
+var ErrMailboxFull = errors.New("spawn: mailbox full")
+
+// WithMailbox sets the number of messages the mailbox of Actor can hold (default is DefaultMailbox).
+func WithMailbox(n int) Option {
+	if n < 0 {
+		panic("negative size for WithMailbox")
+	}
+	return option(kindMailbox, "WithMailbox", func(o *taskOptions) {
+		o.mailbox = n
+	})
+}
+
+// Mailbox is the handle of an actor started by Actor. It sends messages to the actor and
+// controls its task.
+type Mailbox[M any] struct {
+	Handle
+	mu     sync.RWMutex
+	closed bool
+	ch     chan M
+}
+
+// Actor starts a new concurrent task that calls handler with each message sent to the returned
+// Mailbox, one at a time in the order they were sent. It is built on Chan, so the ChanOption
+// options apply, except WithCleanup which is always set: when the context is canceled, the messages
+// pending in the mailbox are still handled before the task completes.
+func Actor[M any](ctx context.Context, handler func(context.Context, M), options ...Option) *Mailbox[M] {
+	t := &task{taskOptions: taskOptions{mailbox: DefaultMailbox}}
+	t.apply("Actor", kindTask|kindChan|kindRestart|kindMailbox, options)
+	t.cleanup = true
+	m := &Mailbox[M]{ch: make(chan M, t.mailbox)}
+	m.Handle = startChan(ctx, t, m.ch, handler)
+	return m
+}
+
+// Send sends the message to the actor, blocking while the mailbox is full.
+// It returns ErrStopped if the mailbox is closed or the actor has stopped.
+func (m *Mailbox[M]) Send(msg M) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrStopped
+	}
+	select {
+	case m.ch <- msg:
+		return nil
+	case <-m.Done():
+		return ErrStopped
+	}
+}
+
+// TrySend is like Send, but returns ErrMailboxFull instead of blocking when the mailbox is full.
+func (m *Mailbox[M]) TrySend(msg M) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrStopped
+	}
+	select {
+	case <-m.Done():
+		return ErrStopped
+	default:
+	}
+	select {
+	case m.ch <- msg:
+		return nil
+	default:
+		return ErrMailboxFull
+	}
+}
+
+// Close closes the mailbox. The actor handles the pending messages and then completes;
+// use Join to wait for it. Close blocks while a Send call is waiting for space in the mailbox.
+func (m *Mailbox[M]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.closed {
+		m.closed = true
+		close(m.ch)
+	}
+}
@@ -0,0 +1,72 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestActor(t *testing.T) {
+	ctx := context.Background()
+	var sum int
+
+	actor := spawn.Actor(ctx, func(ctx context.Context, n int) {
+		sum += n
+	}, spawn.WithMailbox(4))
+	for i := 1; i <= 10; i++ {
+		if err := actor.Send(i); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	actor.Close()
+	actor.Join(ctx)
+
+	if sum != 55 {
+		t.Errorf("Expected sum 55, got %d", sum)
+	}
+	if err := actor.Send(1); !errors.Is(err, spawn.ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+func TestActorDrain(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	var handled int
+
+	actor := spawn.Actor(ctx, func(ctx context.Context, n int) {
+		if n == 0 {
+			<-block
+		}
+		handled++
+	}, spawn.WithMailbox(2))
+	actor.Send(0)
+	actor.Send(1)
+	actor.Send(2)
+	if err := actor.TrySend(3); !errors.Is(err, spawn.ErrMailboxFull) {
+		t.Errorf("Expected ErrMailboxFull, got %v", err)
+	}
+
+	actor.Cancel()
+	close(block)
+	actor.Join(ctx)
+	if handled != 3 {
+		t.Errorf("Expected pending messages to be drained, got %d handled", handled)
+	}
+}
+
+func TestActorOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The spare capacity of the options must not be written by Actor.
+	options := make([]spawn.Option, 1, 4)
+	options[0] = spawn.WithMailbox(1)
+	actor := spawn.Actor(ctx, func(ctx context.Context, n int) {}, options...)
+	if options[:2][1] != nil {
+		t.Error("Expected the options of the caller to be left unchanged")
+	}
+	actor.Close()
+	actor.Join(ctx)
+}
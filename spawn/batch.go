@@ -0,0 +1,143 @@
+package spawn
+
+import (
+	"context"
+	"time"
+)
+
+// Default limits of ChanBatch.
+const (
+	DefaultMaxBatch = 100
+	DefaultMaxDelay = time.Second
+)
+
+// WithMaxBatch sets the maximum number of values in a batch of ChanBatch (default is DefaultMaxBatch).
+func WithMaxBatch(n int) ChanOption {
+	if n <= 0 {
+		panic("non-positive size for WithMaxBatch")
+	}
+	return option(kindBatch, "WithMaxBatch", func(o *taskOptions) {
+		o.maxBatch = n
+	})
+}
+
+// WithMaxDelay sets the maximum time a value waits in a batch of ChanBatch before the batch is flushed
+// (default is DefaultMaxDelay).
+func WithMaxDelay(d time.Duration) ChanOption {
+	if d <= 0 {
+		panic("non-positive delay for WithMaxDelay")
+	}
+	return option(kindBatch, "WithMaxDelay", func(o *taskOptions) {
+		o.maxDelay = d
+	})
+}
+
+// ChanBatch starts a task that collects values from a channel into batches and calls flush with each
+// batch. A batch is flushed when it reaches the maximum size set by WithMaxBatch, or when its first
+// value has waited for the maximum delay set by WithMaxDelay. The batch passed to flush is not reused.
+//
+// When the channel is closed, the pending batch is flushed and the task exits. When the context is
+// canceled, the pending batch is flushed, together with the values remaining in the channel if
+// WithCleanup is set. WithTicker, WithOnClosed and WithSaturation are also supported.
+func ChanBatch[T any](ctx context.Context, ch <-chan T, flush func(context.Context, []T), options ...ChanOption) Handle {
+	t := &task{taskOptions: taskOptions{
+		maxBatch: DefaultMaxBatch,
+		maxDelay: DefaultMaxDelay,
+	}}
+	t.apply("ChanBatch", kindTask|kindChan|kindBatch, options)
+	return startBatch(ctx, t, ch, flush)
+}
+
+// startBatch starts the task t that collects values from ch into batches, like ChanBatch.
+func startBatch[T any](ctx context.Context, t *task, ch <-chan T, flush func(context.Context, []T)) Handle {
+	return start(ctx, t, func(ctx context.Context) error {
+		var tc <-chan time.Time
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
+			defer ticker.Stop()
+			tc = ticker.C
+		}
+		timer := time.NewTimer(t.maxDelay)
+		timer.Stop()
+		defer timer.Stop()
+
+		defer t.sample(ctx, gauge(ch))()
+
+		var batch []T
+		open := 1
+		add := func(v T) {
+			if len(batch) == 0 {
+				timer.Reset(t.maxDelay)
+			}
+			batch = append(batch, v)
+			if len(batch) >= t.maxBatch {
+				timer.Stop()
+				flush(ctx, batch)
+				batch = nil
+			}
+		}
+
+		for {
+			select {
+			case <-tc:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch:
+				if !ok {
+					if len(batch) > 0 {
+						flush(ctx, batch)
+						batch = nil
+					}
+					ch = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
+				add(v)
+			case <-timer.C:
+				if len(batch) > 0 {
+					flush(ctx, batch)
+					batch = nil
+				}
+			case <-ctx.Done():
+				if t.cleanup {
+					cleanup(t, ctx, ch, func(_ context.Context, v T) { add(v) })
+				}
+				if len(batch) > 0 {
+					flush(ctx, batch)
+				}
+				return nil
+			}
+		}
+	})
+}
+
+// Buffer starts a new concurrent task that buffers the values passed to the returned add function and
+// calls flush with the buffered values once maxSize values are buffered, or once the oldest buffered
+// value has waited for flushInterval. When the task is canceled, the remaining values are flushed,
+// and calls to add after that are ignored. It is built on ChanBatch, so the ChanOption options apply,
+// except WithMaxBatch and WithMaxDelay, which are set by maxSize and flushInterval.
+//
+// The add function is safe for concurrent use. It blocks while flush is running, which applies
+// backpressure to the producers. The slice passed to flush is not reused.
+func Buffer[T any](ctx context.Context, flushInterval time.Duration, maxSize int, flush func(context.Context, []T), options ...Option) (add func(T), h Handle) {
+	if maxSize <= 0 {
+		panic("non-positive size for Buffer")
+	}
+	if flushInterval <= 0 {
+		panic("non-positive interval for Buffer")
+	}
+	t := newTask("Buffer", kindTask|kindChan, options)
+	t.maxBatch = maxSize
+	t.maxDelay = flushInterval
+	t.cleanup = true
+	ch := make(chan T)
+	h = startBatch(ctx, t, ch, flush)
+	add = func(v T) {
+		select {
+		case ch <- v:
+		case <-h.Done():
+		}
+	}
+	return add, h
+}
@@ -0,0 +1,79 @@
+package spawn_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestChanBatch(t *testing.T) {
+	ctx := context.Background()
+	ch := make(chan int)
+	var mu sync.Mutex
+	var batches [][]int
+
+	handle := spawn.ChanBatch(ctx, ch, func(ctx context.Context, batch []int) {
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}, spawn.WithMaxBatch(3), spawn.WithMaxDelay(50*time.Millisecond))
+
+	for i := range 5 {
+		ch <- i
+	}
+	time.Sleep(100 * time.Millisecond)
+	ch <- 5
+	handle.Cancel()
+	handle.Join(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 3 {
+		t.Fatalf("Expected 3 batches, got %v", batches)
+	}
+	if len(batches[0]) != 3 || len(batches[1]) != 2 || len(batches[2]) != 1 {
+		t.Errorf("Expected batches of size 3, 2 and 1, got %v", batches)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var batches [][]int
+
+	add, handle := spawn.Buffer(ctx, 50*time.Millisecond, 3, func(ctx context.Context, batch []int) {
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	})
+	for i := range 4 {
+		add(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	add(4)
+	handle.Cancel()
+	handle.Join(ctx)
+	add(5)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 3 || len(batches[0]) != 3 || len(batches[1]) != 1 || len(batches[2]) != 1 {
+		t.Errorf("Expected batches of size 3, 1 and 1, got %v", batches)
+	}
+}
+
+func TestBufferOptions(t *testing.T) {
+	ctx := context.Background()
+	// The spare capacity of the options must not be written by Buffer.
+	options := make([]spawn.Option, 1, 4)
+	options[0] = spawn.Named("buffer")
+	_, handle := spawn.Buffer(ctx, time.Second, 3, func(ctx context.Context, batch []int) {}, options...)
+	if options[:2][1] != nil {
+		t.Error("Expected the options of the caller to be left unchanged")
+	}
+	handle.Cancel()
+	handle.Join(ctx)
+}
@@ -0,0 +1,54 @@
+package spawn
+
+import (
+	"context"
+	"sync"
+)
+
+// Bridge adapts a callback-style API to a channel. It starts a new concurrent task that calls
+// subscribe with an emit function, which sends each value to the returned channel, blocking
+// until it is received or the task is canceled. When the task is canceled, the unsubscribe
+// function returned by subscribe is called and the channel is closed; values emitted after
+// that are dropped. If subscribe fails, the channel is closed and the error is reported by
+// the Err method of the handle.
+func Bridge[T any](ctx context.Context, subscribe func(emit func(T)) (unsubscribe func(), err error), options ...Option) (<-chan T, Handle) {
+	t := newTask("Bridge", kindTask, options)
+	ch := make(chan T)
+	var (
+		mu     sync.RWMutex
+		closed bool
+		done   <-chan struct{}
+	)
+	emit := func(v T) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- v:
+		case <-done:
+		}
+	}
+	h := start(ctx, t, func(ctx context.Context) error {
+		defer func() {
+			mu.Lock()
+			closed = true
+			close(ch)
+			mu.Unlock()
+		}()
+		mu.Lock()
+		done = ctx.Done()
+		mu.Unlock()
+		unsubscribe, err := subscribe(emit)
+		if err != nil {
+			return err
+		}
+		<-ctx.Done()
+		if unsubscribe != nil {
+			unsubscribe()
+		}
+		return nil
+	})
+	return ch, h
+}
@@ -0,0 +1,93 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+// notifier is a callback-style API.
+type notifier struct {
+	mu        sync.Mutex
+	listeners map[int]func(string)
+	next      int
+}
+
+func (n *notifier) listen(f func(string)) (cancel func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.listeners == nil {
+		n.listeners = make(map[int]func(string))
+	}
+	id := n.next
+	n.next++
+	n.listeners[id] = f
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.listeners, id)
+	}
+}
+
+func (n *notifier) notify(s string) {
+	n.mu.Lock()
+	listeners := make([]func(string), 0, len(n.listeners))
+	for _, f := range n.listeners {
+		listeners = append(listeners, f)
+	}
+	n.mu.Unlock()
+	for _, f := range listeners {
+		f(s)
+	}
+}
+
+func (n *notifier) len() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.listeners)
+}
+
+func TestBridge(t *testing.T) {
+	ctx := context.Background()
+	var n notifier
+	subscribed := make(chan struct{})
+
+	ch, handle := spawn.Bridge(ctx, func(emit func(string)) (func(), error) {
+		defer close(subscribed)
+		return n.listen(emit), nil
+	})
+	<-subscribed
+	go n.notify("hello")
+	if s := <-ch; s != "hello" {
+		t.Errorf("Expected %q, got %q", "hello", s)
+	}
+
+	handle.Cancel()
+	handle.Join(ctx)
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected channel to be closed")
+	}
+	if n.len() != 0 {
+		t.Errorf("Expected listener to be unsubscribed")
+	}
+	n.notify("dropped")
+}
+
+func TestBridgeError(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+
+	ch, handle := spawn.Bridge(ctx, func(emit func(int)) (func(), error) {
+		return nil, errBad
+	})
+	handle.Join(ctx)
+	if !errors.Is(handle.Err(), errBad) {
+		t.Errorf("Expected error %v, got %v", errBad, handle.Err())
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("Expected channel to be closed")
+	}
+}
@@ -0,0 +1,234 @@
+package spawn
+
+import (
+	"context"
+	"sync"
+)
+
+// SlowPolicy is the policy of a Broadcaster for subscribers that do not keep up with the source.
+type SlowPolicy int
+
+const (
+	// SlowBlock waits for each subscriber to receive each value, so the slowest subscriber
+	// limits the rate of all subscribers. It is the default.
+	SlowBlock SlowPolicy = iota
+	// SlowDrop drops the values that do not fit in the channel buffer of a subscriber.
+	SlowDrop
+	// SlowBuffer queues the values that do not fit in the channel buffer of a subscriber,
+	// without limit, so a slow subscriber does not affect the others.
+	SlowBuffer
+)
+
+// BroadcastOption is a configuration option for the Broadcast function.
+type BroadcastOption func(*broadcastOptions)
+
+type broadcastOptions struct {
+	policy SlowPolicy
+	buffer int
+}
+
+// WithSlowPolicy sets the policy for slow subscribers (default is SlowBlock).
+func WithSlowPolicy(policy SlowPolicy) BroadcastOption {
+	return func(o *broadcastOptions) {
+		o.policy = policy
+	}
+}
+
+// WithSubscriberBuffer sets the buffer size of the channel of each subscriber (default is 0).
+func WithSubscriberBuffer(n int) BroadcastOption {
+	if n < 0 {
+		panic("negative buffer for WithSubscriberBuffer")
+	}
+	return func(o *broadcastOptions) {
+		o.buffer = n
+	}
+}
+
+// Broadcaster sends each value from a source channel to all of its subscribers.
+// It is created by Broadcast.
+type Broadcaster[T any] struct {
+	Handle
+	options broadcastOptions
+
+	mu      sync.Mutex
+	subs    map[<-chan T]*subscriber[T]
+	stopped bool
+}
+
+// Broadcast starts a new concurrent task that sends each value received from src to all subscribers
+// of the returned Broadcaster, until src is closed or the context is canceled. Then the channels of
+// all subscribers are closed, after the values queued for SlowBuffer subscribers are received.
+func Broadcast[T any](ctx context.Context, src <-chan T, options ...BroadcastOption) *Broadcaster[T] {
+	b := &Broadcaster[T]{subs: make(map[<-chan T]*subscriber[T])}
+	for _, opt := range options {
+		opt(&b.options)
+	}
+	b.Handle = Run(ctx, func(ctx context.Context) {
+		defer b.stop()
+		for {
+			select {
+			case v, ok := <-src:
+				if !ok {
+					return
+				}
+				b.mu.Lock()
+				subs := make([]*subscriber[T], 0, len(b.subs))
+				for _, s := range b.subs {
+					subs = append(subs, s)
+				}
+				b.mu.Unlock()
+				for _, s := range subs {
+					s.deliver(ctx, v)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return b
+}
+
+// Subscribe returns a new channel that receives the values of the source from now on.
+// If the broadcaster has stopped, the returned channel is closed.
+func (b *Broadcaster[T]) Subscribe() <-chan T {
+	s := newSubscriber[T](b.options)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		s.stop(false)
+	} else {
+		b.subs[s.ch] = s
+	}
+	return s.ch
+}
+
+// Unsubscribe stops sending values to the channel returned by Subscribe, and closes it.
+// Values queued for the subscriber are dropped.
+func (b *Broadcaster[T]) Unsubscribe(ch <-chan T) {
+	b.mu.Lock()
+	s, ok := b.subs[ch]
+	delete(b.subs, ch)
+	b.mu.Unlock()
+	if ok {
+		s.stop(false)
+	}
+}
+
+// stop closes the channels of all subscribers, after the values queued for them are received.
+func (b *Broadcaster[T]) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	for _, s := range b.subs {
+		s.stop(true)
+	}
+}
+
+type subscriber[T any] struct {
+	policy SlowPolicy
+	ch     chan T
+	// done is closed when the subscriber is unsubscribed, aborting pending sends.
+	done     chan struct{}
+	doneOnce sync.Once
+	once     sync.Once
+
+	mu     sync.Mutex
+	closed bool
+	queue  []T
+	// signal wakes up the forwarder goroutine of a SlowBuffer subscriber.
+	signal chan struct{}
+	// finish is closed when the broadcaster stops, for the forwarder goroutine to flush the queue.
+	finish chan struct{}
+}
+
+func newSubscriber[T any](o broadcastOptions) *subscriber[T] {
+	s := &subscriber[T]{
+		policy: o.policy,
+		ch:     make(chan T, o.buffer),
+		done:   make(chan struct{}),
+	}
+	if s.policy == SlowBuffer {
+		s.signal = make(chan struct{}, 1)
+		s.finish = make(chan struct{})
+		go s.forward()
+	}
+	return s
+}
+
+// deliver sends v to the subscriber according to its policy.
+func (s *subscriber[T]) deliver(ctx context.Context, v T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	switch s.policy {
+	case SlowDrop:
+		select {
+		case s.ch <- v:
+		default:
+		}
+	case SlowBuffer:
+		s.queue = append(s.queue, v)
+		select {
+		case s.signal <- struct{}{}:
+		default:
+		}
+	default:
+		select {
+		case s.ch <- v:
+		case <-s.done:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// forward sends the queued values of a SlowBuffer subscriber to its channel.
+func (s *subscriber[T]) forward() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		queue := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, v := range queue {
+			select {
+			case s.ch <- v:
+			case <-s.done:
+				return
+			}
+		}
+		select {
+		case <-s.signal:
+		case <-s.finish:
+			s.mu.Lock()
+			n := len(s.queue)
+			s.mu.Unlock()
+			if n == 0 {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// stop stops the subscriber and closes its channel. If flush is true, the values queued for
+// a SlowBuffer subscriber are still sent before the channel is closed.
+func (s *subscriber[T]) stop(flush bool) {
+	if !flush {
+		s.doneOnce.Do(func() {
+			close(s.done)
+		})
+	}
+	s.once.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		if s.policy == SlowBuffer {
+			close(s.finish)
+		} else {
+			close(s.ch)
+		}
+	})
+}
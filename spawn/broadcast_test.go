@@ -0,0 +1,95 @@
+package spawn_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func collect[T any](ch <-chan T) <-chan []T {
+	result := make(chan []T, 1)
+	go func() {
+		var values []T
+		for v := range ch {
+			values = append(values, v)
+		}
+		result <- values
+	}()
+	return result
+}
+
+func TestBroadcast(t *testing.T) {
+	ctx := context.Background()
+	src := make(chan int)
+	b := spawn.Broadcast(ctx, src)
+
+	r1 := collect(b.Subscribe())
+	r2 := collect(b.Subscribe())
+	for i := range 5 {
+		src <- i
+	}
+	close(src)
+	b.Join(ctx)
+
+	want := []int{0, 1, 2, 3, 4}
+	for _, r := range []<-chan []int{r1, r2} {
+		if got := <-r; !slices.Equal(got, want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+	if _, ok := <-b.Subscribe(); ok {
+		t.Errorf("Expected closed channel after broadcaster stopped")
+	}
+}
+
+func TestBroadcastUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	src := make(chan int)
+	b := spawn.Broadcast(ctx, src)
+	defer b.Cancel()
+
+	ch := b.Subscribe()
+	other := collect(b.Subscribe())
+	src <- 1
+	if v := <-ch; v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	// ch is not read anymore, but the blocked send is aborted by Unsubscribe.
+	src <- 2
+	time.Sleep(10 * time.Millisecond)
+	b.Unsubscribe(ch)
+	src <- 3
+	close(src)
+	if got := <-other; !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3], got %v", got)
+	}
+}
+
+func TestBroadcastSlowPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	src := make(chan int)
+	b := spawn.Broadcast(ctx, src, spawn.WithSlowPolicy(spawn.SlowDrop), spawn.WithSubscriberBuffer(2))
+	slow := b.Subscribe()
+	for i := range 5 {
+		src <- i
+	}
+	close(src)
+	if got := <-collect(slow); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("Expected [0 1] with SlowDrop, got %v", got)
+	}
+
+	src = make(chan int)
+	b = spawn.Broadcast(ctx, src, spawn.WithSlowPolicy(spawn.SlowBuffer))
+	slow = b.Subscribe()
+	for i := range 5 {
+		src <- i
+	}
+	close(src)
+	if got := <-collect(slow); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Expected [0 1 2 3 4] with SlowBuffer, got %v", got)
+	}
+}
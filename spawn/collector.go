@@ -0,0 +1,95 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// TaskError is an error returned by a task, as reported to a Collector.
+type TaskError struct {
+	// Name is the name of the task given with Named, or the empty string.
+	Name string
+	// Err is the error returned by the task.
+	Err error
+	// Time is the time the task completed.
+	Time time.Time
+}
+
+// Error returns the error message, prefixed with the task name if any.
+func (e TaskError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+	return "task " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the task.
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
+
+// Collector receives the errors of tasks started with WithCollector, so that failures of
+// fire-and-forget tasks can be logged or alerted on in one place. Errors are delivered either
+// to a channel (see NewCollector) or to a function (see CollectorFunc).
+type Collector struct {
+	f       func(TaskError)
+	ch      chan TaskError
+	dropped atomic.Int64
+}
+
+// NewCollector creates a Collector that delivers errors to the channel returned by C, which
+// buffers up to size errors. Errors are dropped, and counted by Dropped, when the buffer is full.
+func NewCollector(size int) *Collector {
+	if size <= 0 {
+		panic("non-positive size for NewCollector")
+	}
+	return &Collector{ch: make(chan TaskError, size)}
+}
+
+// CollectorFunc creates a Collector that calls f with each error, on the goroutine of the failed task.
+func CollectorFunc(f func(TaskError)) *Collector {
+	if f == nil {
+		panic("nil function for CollectorFunc")
+	}
+	return &Collector{f: f}
+}
+
+// WithCollector reports the error of the task to the collector c if the task fails.
+// A context.Canceled error returned after the task is canceled is not reported.
+func WithCollector(c *Collector) Option {
+	if c == nil {
+		panic("nil collector for WithCollector")
+	}
+	return option(kindTask, "WithCollector", func(o *taskOptions) {
+		o.collector = c
+	})
+}
+
+// C returns the channel of errors of a Collector created by NewCollector, or nil.
+func (c *Collector) C() <-chan TaskError {
+	return c.ch
+}
+
+// Dropped returns the number of errors dropped because the channel buffer was full.
+func (c *Collector) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// report reports the error err of the task with the given name and context.
+func (c *Collector) report(ctx context.Context, name string, err error) {
+	if err == nil || (ctx.Err() != nil && errors.Is(err, context.Canceled)) {
+		return
+	}
+	e := TaskError{Name: name, Err: err, Time: time.Now()}
+	if c.f != nil {
+		c.f(e)
+		return
+	}
+	select {
+	case c.ch <- e:
+	default:
+		c.dropped.Add(1)
+	}
+}
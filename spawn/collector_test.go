@@ -0,0 +1,61 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+	collector := spawn.NewCollector(1)
+
+	spawn.RunErr(ctx, func(ctx context.Context) error {
+		return errBad
+	}, spawn.Named("test-collector"), spawn.WithCollector(collector))
+	select {
+	case e := <-collector.C():
+		if e.Name != "test-collector" || !errors.Is(e, errBad) {
+			t.Errorf("Expected error %v of test-collector, got %v", errBad, e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected error to be collected")
+	}
+
+	h := spawn.RunErr(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, spawn.WithCollector(collector))
+	h.Cancel()
+	h.Join(ctx)
+	for range 2 {
+		spawn.RunErr(ctx, func(ctx context.Context) error {
+			return errBad
+		}, spawn.WithCollector(collector)).Join(ctx)
+	}
+	if n := collector.Dropped(); n != 1 {
+		t.Errorf("Expected 1 dropped error, got %d", n)
+	}
+}
+
+func TestCollectorFunc(t *testing.T) {
+	ctx := context.Background()
+	var got []spawn.TaskError
+	collector := spawn.CollectorFunc(func(e spawn.TaskError) {
+		got = append(got, e)
+	})
+
+	spawn.RunErr(ctx, func(ctx context.Context) error {
+		return errors.New("bad")
+	}, spawn.WithCollector(collector)).Join(ctx)
+	spawn.RunErr(ctx, func(ctx context.Context) error {
+		return nil
+	}, spawn.WithCollector(collector)).Join(ctx)
+	if len(got) != 1 || got[0].Error() != "bad" {
+		t.Errorf("Expected 1 collected error, got %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package spawn
+
+import (
+	"context"
+	"time"
+)
+
+// MergeContexts returns a context that is canceled when any of the given contexts is canceled,
+// or when the returned cancel function is called. Its deadline is the earliest deadline of the
+// contexts, its cause is the cause of the first canceled context, and its values are looked up
+// in the contexts in order, the first found wins.
+//
+// It is useful for tasks that must honor both a request context and a server lifecycle context.
+// Calling the cancel function releases the resources associated with the merged context.
+func MergeContexts(ctxs ...context.Context) (context.Context, context.CancelFunc) {
+	if len(ctxs) == 0 {
+		return context.WithCancel(context.Background())
+	}
+	ctx, cancelCause := context.WithCancelCause(mergedValues{ctxs[0], ctxs})
+	stops := make([]func() bool, 0, len(ctxs)-1)
+	var deadline time.Time
+	for _, parent := range ctxs[1:] {
+		stops = append(stops, context.AfterFunc(parent, func() {
+			// An exceeded deadline is handled by the deadline of the merged context,
+			// so that its error is context.DeadlineExceeded.
+			if parent.Err() != context.DeadlineExceeded {
+				cancelCause(context.Cause(parent))
+			}
+		}))
+		if d, ok := parent.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+			deadline = d
+		}
+	}
+	cancelDeadline := context.CancelFunc(func() {})
+	if d, ok := ctxs[0].Deadline(); !deadline.IsZero() && (!ok || deadline.Before(d)) {
+		ctx, cancelDeadline = context.WithDeadline(ctx, deadline)
+	}
+	return ctx, func() {
+		cancelDeadline()
+		cancelCause(nil)
+		for _, stop := range stops {
+			stop()
+		}
+	}
+}
+
+// mergedValues is a context that looks up values in all parents.
+type mergedValues struct {
+	context.Context
+	parents []context.Context
+}
+
+// Value returns the first value associated with key in the parents.
+func (c mergedValues) Value(key any) any {
+	for _, parent := range c.parents {
+		if v := parent.Value(key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
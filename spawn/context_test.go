@@ -0,0 +1,61 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+type ctxKey string
+
+func TestMergeContexts(t *testing.T) {
+	request := context.WithValue(context.Background(), ctxKey("id"), "request")
+	request = context.WithValue(request, ctxKey("user"), "alice")
+	server, stop := context.WithCancelCause(context.WithValue(context.Background(), ctxKey("id"), "server"))
+
+	ctx, cancel := spawn.MergeContexts(request, server)
+	defer cancel()
+	if v := ctx.Value(ctxKey("id")); v != "request" {
+		t.Errorf("Expected first value to win, got %v", v)
+	}
+	if v := ctx.Value(ctxKey("user")); v != "alice" {
+		t.Errorf("Expected value from first context, got %v", v)
+	}
+
+	errShutdown := errors.New("shutdown")
+	stop(errShutdown)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Expected merged context to be canceled")
+	}
+	if !errors.Is(context.Cause(ctx), errShutdown) {
+		t.Errorf("Expected cause %v, got %v", errShutdown, context.Cause(ctx))
+	}
+}
+
+func TestMergeContextsDeadline(t *testing.T) {
+	long, cancel1 := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel1()
+	short, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+
+	ctx, cancel := spawn.MergeContexts(long, short)
+	defer cancel()
+	if d, ok := ctx.Deadline(); !ok || time.Until(d) > time.Second {
+		t.Errorf("Expected earliest deadline, got %v", d)
+	}
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", ctx.Err())
+	}
+
+	ctx, cancel = spawn.MergeContexts(context.Background(), context.Background())
+	cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Expected canceled, got %v", ctx.Err())
+	}
+}
@@ -0,0 +1,225 @@
+package spawn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron starts a new concurrent task that calls f at the times given by the cron expression spec,
+// in the local time zone or the location given by InLocation, until the context is canceled.
+//
+// The spec has 5 fields (minute, hour, day of month, month, day of week) or 6 fields with a leading
+// seconds field. Each field is "*", "?", a value, a range "a-b", or a comma-separated list of those,
+// optionally followed by a step "/n". Months and days of week may be given by their three-letter
+// English names. Day of week 0 and 7 are Sunday. As in standard cron, if both the day of month and
+// the day of week are restricted, a time matches if either field matches.
+//
+// Calls to f do not overlap: a run that is due while f is still running is skipped.
+func Cron(ctx context.Context, spec string, f func(context.Context), options ...Option) (Handle, error) {
+	s, err := parseCron(spec)
+	if err != nil {
+		return nil, err
+	}
+	t := newTask("Cron", kindTask|kindPeriodic|kindRestart|kindLocation, options)
+	loc := t.location
+	if loc == nil {
+		loc = time.Local
+	}
+	f = t.counted(f)
+	return start(ctx, t, func(ctx context.Context) error {
+		for {
+			now := time.Now().In(loc)
+			next := s.next(now)
+			if next.IsZero() {
+				return nil
+			}
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C:
+				f(ctx)
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			}
+		}
+	}), nil
+}
+
+// InLocation makes Cron and Every follow the wall clock of the location rather than the local time
+// or UTC, including across daylight saving time transitions: a daily run at 09:00 runs at 09:00 in
+// the location on both sides of a transition.
+func InLocation(loc *time.Location) Option {
+	if loc == nil {
+		panic("nil location for InLocation")
+	}
+	return option(kindLocation, "InLocation", func(o *taskOptions) {
+		o.location = loc
+	})
+}
+
+// inLocation returns the time in the location with the same wall clock as the UTC time wall.
+func inLocation(wall time.Time, loc *time.Location) time.Time {
+	y, m, d := wall.Date()
+	return time.Date(y, m, d, wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+}
+
+// cronSchedule holds the set of matching values of each cron field as a bit mask.
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar and dowStar report whether the day of month and day of week fields are unrestricted.
+	domStar, dowStar bool
+}
+
+// cronField describes the range and value names of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	cronSecond = cronField{name: "second", min: 0, max: 59}
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+// parseCron parses a 5 or 6 field cron expression.
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("spawn: invalid cron spec %q: expected 5 or 6 fields, got %d", spec, len(fields))
+	}
+	s := &cronSchedule{
+		domStar: fields[3] == "*" || fields[3] == "?",
+		dowStar: fields[5] == "*" || fields[5] == "?",
+	}
+	for i, p := range []struct {
+		mask  *uint64
+		field cronField
+	}{
+		{&s.second, cronSecond},
+		{&s.minute, cronMinute},
+		{&s.hour, cronHour},
+		{&s.dom, cronDom},
+		{&s.month, cronMonth},
+		{&s.dow, cronDow},
+	} {
+		mask, err := p.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("spawn: invalid cron spec %q: %w", spec, err)
+		}
+		*p.mask = mask
+	}
+	// Sunday may be written as 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parse parses a comma-separated list of ranges of the field into a bit mask.
+func (f cronField) parse(expr string) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(expr, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepStr, f.name)
+			}
+			step = n
+		}
+		if rng != "*" && rng != "?" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(first); err != nil {
+				return 0, err
+			}
+			if isRange {
+				if hi, err = f.value(last); err != nil {
+					return 0, err
+				}
+			} else if !hasStep {
+				hi = lo
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", rng, f.name)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << v
+		}
+	}
+	return mask, nil
+}
+
+// value parses a single value or name of the field.
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	return v, nil
+}
+
+// dayMatches reports whether the day of t matches the day of month and day of week fields.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<t.Day()) != 0
+	dowMatch := s.dow&(1<<t.Weekday()) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time after t that matches the schedule, or the zero time if there is none
+// within the next five years.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<t.Month()) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if s.second&(1<<t.Second()) == 0 {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,96 @@
+package spawn_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestCron(t *testing.T) {
+	ctx := context.Background()
+	called := make(chan time.Time, 1)
+
+	handle, err := spawn.Cron(ctx, "* * * * * *", func(ctx context.Context) {
+		select {
+		case called <- time.Now():
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer handle.Cancel()
+
+	select {
+	case now := <-called:
+		if now.Sub(now.Truncate(time.Second)) > 500*time.Millisecond {
+			t.Errorf("Expected function to be called at the start of a second, got %v", now)
+		}
+	case <-time.After(3 * time.Second):
+		t.Errorf("Expected function to be called")
+	}
+}
+
+func TestCronSpec(t *testing.T) {
+	valid := []string{
+		"30 2 * * *",
+		"*/15 * * * *",
+		"0 0 1,15 * MON-FRI",
+		"0 9-17/2 * jan-jun 7",
+		"0 30 2 * * ?",
+	}
+	for _, spec := range valid {
+		h, err := spawn.Cron(context.Background(), spec, func(ctx context.Context) {})
+		if err != nil {
+			t.Errorf("Expected spec %q to be valid, got %v", spec, err)
+			continue
+		}
+		h.Cancel()
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	}
+	for _, spec := range invalid {
+		if _, err := spawn.Cron(context.Background(), spec, func(ctx context.Context) {}); err == nil {
+			t.Errorf("Expected spec %q to be invalid", spec)
+		}
+	}
+}
+
+func TestCronInLocation(t *testing.T) {
+	ctx := context.Background()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone database not available: %v", err)
+	}
+	called := make(chan time.Time, 1)
+
+	handle, err := spawn.Cron(ctx, "* * * * * *", func(ctx context.Context) {
+		select {
+		case called <- time.Now():
+		default:
+		}
+	}, spawn.InLocation(loc))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer handle.Cancel()
+
+	select {
+	case <-called:
+	case <-time.After(3 * time.Second):
+		t.Errorf("Expected function to be called")
+	}
+}
@@ -0,0 +1,70 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ForEachSeq calls f for each value of seq with at most workers concurrent calls. The sequence is
+// consumed on the calling goroutine, no faster than the workers process it, so it may be unbounded.
+//
+// By default, the first error cancels the context passed to the other calls, stops consuming the
+// sequence, and is returned. With WithCollectAll, all values are processed and all errors are
+// returned joined. If the context is canceled, consuming the sequence stops and the context error
+// is returned.
+func ForEachSeq[T any](ctx context.Context, seq iter.Seq[T], workers int, f func(context.Context, T) error, options ...Option) error {
+	if workers <= 0 {
+		panic("non-positive workers for ForEachSeq")
+	}
+	var o taskOptions
+	o.apply("ForEachSeq", kindCollectAll, options)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	values := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for v := range values {
+				if err := f(ctx, v); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					if !o.collectAll {
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+	canceled := false
+	for v := range seq {
+		select {
+		case values <- v:
+			continue
+		case <-ctx.Done():
+			canceled = true
+		}
+		break
+	}
+	close(values)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		if o.collectAll {
+			return errors.Join(errs...)
+		}
+		return errs[0]
+	}
+	if canceled {
+		return ctx.Err()
+	}
+	return nil
+}
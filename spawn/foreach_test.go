@@ -0,0 +1,68 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+// naturals returns an unbounded sequence of natural numbers.
+func naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestForEachSeq(t *testing.T) {
+	ctx := context.Background()
+	var sum int64
+
+	err := spawn.ForEachSeq(ctx, slices.Values([]int{1, 2, 3, 4, 5}), 3, func(ctx context.Context, x int) error {
+		atomic.AddInt64(&sum, int64(x))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sum != 15 {
+		t.Errorf("Expected sum 15, got %d", sum)
+	}
+}
+
+func TestForEachSeqFailFast(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+
+	err := spawn.ForEachSeq(ctx, naturals(), 4, func(ctx context.Context, x int) error {
+		if x == 100 {
+			return errBad
+		}
+		return nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Errorf("Expected error %v, got %v", errBad, err)
+	}
+}
+
+func TestForEachSeqCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := spawn.ForEachSeq(ctx, naturals(), 2, func(ctx context.Context, x int) error {
+		if x == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+}
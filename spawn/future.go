@@ -0,0 +1,43 @@
+package spawn
+
+import "context"
+
+// Future is the handle of a task that returns a value. It is created by Async.
+type Future[T any] struct {
+	*taskHandle
+	value T
+}
+
+// Async starts a new concurrent task that calls f and returns a Future for its result.
+// Canceling the Future cancels the context passed to f.
+func Async[T any](ctx context.Context, f func(context.Context) (T, error), options ...Option) *Future[T] {
+	fut := new(Future[T])
+	fut.taskHandle = start(ctx, newTask("Async", kindTask, options), func(ctx context.Context) (err error) {
+		fut.value, err = f(ctx)
+		return err
+	})
+	return fut
+}
+
+// Await waits for the task to complete and returns its result.
+// If the context is canceled first, it returns the zero value and the context error.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.exited:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// TryGet returns the result of the task without blocking.
+// The ok result reports whether the task has completed.
+func (f *Future[T]) TryGet() (value T, err error, ok bool) {
+	select {
+	case <-f.exited:
+		return f.value, f.err, true
+	default:
+		return value, nil, false
+	}
+}
@@ -0,0 +1,50 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestAsync(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+
+	fut := spawn.Async(ctx, func(ctx context.Context) (int, error) {
+		<-release
+		return 42, nil
+	})
+	if _, _, ok := fut.TryGet(); ok {
+		t.Errorf("Expected future not to be completed")
+	}
+	close(release)
+	v, err := fut.Await(ctx)
+	if v != 42 || err != nil {
+		t.Errorf("Expected (42, nil), got (%d, %v)", v, err)
+	}
+	if v, err, ok := fut.TryGet(); !ok || v != 42 || err != nil {
+		t.Errorf("Expected (42, nil, true), got (%d, %v, %v)", v, err, ok)
+	}
+}
+
+func TestAsyncCancel(t *testing.T) {
+	ctx := context.Background()
+
+	fut := spawn.Async(ctx, func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := fut.Await(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	fut.Cancel()
+	if _, err := fut.Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package spawn
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// GoPool reuses goroutines to run tasks, reducing the cost of starting goroutines for workloads
+// that run many short tasks. A task started with WithPool runs on an idle goroutine of the pool
+// if there is one, or on a new goroutine otherwise, so starting a task never blocks. After a task
+// completes, its goroutine waits for another task for up to the idle timeout before exiting.
+//
+// Reuse pays off mostly for tasks that grow their goroutine stack, which is kept by a reused
+// goroutine; for trivial tasks, starting a new goroutine is about as cheap. See the benchmarks.
+type GoPool struct {
+	maxIdle     int
+	idleTimeout time.Duration
+
+	mu   sync.Mutex
+	idle []chan func()
+}
+
+// NewGoPool creates a GoPool that keeps at most maxIdle idle goroutines, each for at most idleTimeout.
+func NewGoPool(maxIdle int, idleTimeout time.Duration) *GoPool {
+	if maxIdle <= 0 {
+		panic("non-positive maxIdle for NewGoPool")
+	}
+	if idleTimeout <= 0 {
+		panic("non-positive idleTimeout for NewGoPool")
+	}
+	return &GoPool{maxIdle: maxIdle, idleTimeout: idleTimeout}
+}
+
+// WithPool makes the task run on a goroutine of the pool p.
+func WithPool(p *GoPool) Option {
+	if p == nil {
+		panic("nil pool for WithPool")
+	}
+	return option(kindTask, "WithPool", func(o *taskOptions) {
+		o.pool = p
+	})
+}
+
+// Idle returns the number of idle goroutines in the pool.
+func (p *GoPool) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle)
+}
+
+// Go runs f on an idle goroutine of the pool, or on a new goroutine if there is none.
+func (p *GoPool) Go(f func()) {
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		w := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		w <- f
+		return
+	}
+	p.mu.Unlock()
+	go p.work(f)
+}
+
+// work runs f and then the tasks sent to it while it is idle, until it times out.
+func (p *GoPool) work(f func()) {
+	w := make(chan func(), 1)
+	timer := time.NewTimer(p.idleTimeout)
+	defer timer.Stop()
+	for {
+		f()
+
+		p.mu.Lock()
+		if len(p.idle) >= p.maxIdle {
+			p.mu.Unlock()
+			return
+		}
+		p.idle = append(p.idle, w)
+		p.mu.Unlock()
+
+		timer.Reset(p.idleTimeout)
+		select {
+		case f = <-w:
+			timer.Stop()
+		case <-timer.C:
+			p.mu.Lock()
+			if i := slices.Index(p.idle, w); i >= 0 {
+				p.idle = slices.Delete(p.idle, i, i+1)
+				p.mu.Unlock()
+				return
+			}
+			p.mu.Unlock()
+			// A task was handed to this goroutine concurrently with the timeout.
+			f = <-w
+		}
+	}
+}
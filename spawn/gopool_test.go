@@ -0,0 +1,100 @@
+package spawn_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestWithPool(t *testing.T) {
+	ctx := context.Background()
+	pool := spawn.NewGoPool(4, time.Second)
+	var called int32
+
+	for range 3 {
+		handles := make([]spawn.Handle, 10)
+		for i := range handles {
+			handles[i] = spawn.Run(ctx, func(ctx context.Context) {
+				atomic.AddInt32(&called, 1)
+			}, spawn.WithPool(pool))
+		}
+		spawn.WaitAll(ctx, handles...)
+	}
+	if n := atomic.LoadInt32(&called); n != 30 {
+		t.Errorf("Expected 30 calls, got %d", n)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := pool.Idle(); n == 0 || n > 4 {
+		t.Errorf("Expected between 1 and 4 idle goroutines, got %d", n)
+	}
+}
+
+func TestGoPoolIdleTimeout(t *testing.T) {
+	pool := spawn.NewGoPool(4, 10*time.Millisecond)
+	done := make(chan struct{})
+	pool.Go(func() { close(done) })
+	<-done
+	time.Sleep(50 * time.Millisecond)
+	if n := pool.Idle(); n != 0 {
+		t.Errorf("Expected idle goroutines to exit, got %d", n)
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	ctx := context.Background()
+	f := func(ctx context.Context) {}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			spawn.Run(ctx, f).Join(ctx)
+		}
+	})
+}
+
+func BenchmarkRunWithPool(b *testing.B) {
+	ctx := context.Background()
+	f := func(ctx context.Context) {}
+	pool := spawn.NewGoPool(1024, time.Second)
+	option := spawn.WithPool(pool)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			spawn.Run(ctx, f, option).Join(ctx)
+		}
+	})
+}
+
+//go:noinline
+func deepStack(n int) int {
+	var buf [256]byte
+	if n == 0 {
+		return int(buf[0])
+	}
+	return deepStack(n-1) + int(buf[n%len(buf)])
+}
+
+func BenchmarkRunDeepStack(b *testing.B) {
+	ctx := context.Background()
+	f := func(ctx context.Context) { deepStack(64) }
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			spawn.Run(ctx, f).Join(ctx)
+		}
+	})
+}
+
+func BenchmarkRunDeepStackWithPool(b *testing.B) {
+	ctx := context.Background()
+	f := func(ctx context.Context) { deepStack(64) }
+	option := spawn.WithPool(spawn.NewGoPool(1024, time.Second))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			spawn.Run(ctx, f, option).Join(ctx)
+		}
+	})
+}
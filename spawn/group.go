@@ -0,0 +1,77 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group supervises multiple tasks: it waits for, cancels, and collects the errors of all tasks started
+// with its Run method. The zero value is ready to use. A Group must not be copied after first use.
+type Group struct {
+	mu       sync.Mutex
+	handles  []Handle
+	errs     []error
+	canceled bool
+	wg       sync.WaitGroup
+}
+
+// Run starts a new concurrent task in the group with the given context and function.
+// The error returned by the function, if any, is collected by the group and returned by
+// the Err method of the handle of the task. If the group has been canceled, the task starts
+// with a canceled context.
+func (g *Group) Run(ctx context.Context, f func(context.Context) error) Handle {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.wg.Add(1)
+	h := RunErr(ctx, func(ctx context.Context) error {
+		defer g.wg.Done()
+		err := f(ctx)
+		if err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+		return err
+	})
+	if g.canceled {
+		h.Cancel()
+	}
+	g.handles = append(g.handles, h)
+	return h
+}
+
+// Join waits for all tasks in the group to complete or the context to be canceled.
+func (g *Group) Join(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+}
+
+// Cancel stops the execution of all tasks in the group, including tasks started later.
+func (g *Group) Cancel() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.canceled = true
+	for _, h := range g.handles {
+		h.Cancel()
+	}
+}
+
+// Errors returns the errors returned by the tasks that have completed so far, in completion order.
+func (g *Group) Errors() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// Err returns the errors returned by the tasks that have completed so far, joined with errors.Join, or nil.
+func (g *Group) Err() error {
+	return errors.Join(g.Errors()...)
+}
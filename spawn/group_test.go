@@ -0,0 +1,67 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestGroup(t *testing.T) {
+	ctx := context.Background()
+	var g spawn.Group
+	var called int32
+	errBad := errors.New("bad")
+
+	var handles []spawn.Handle
+	for i := 0; i < 5; i++ {
+		handles = append(handles, g.Run(ctx, func(ctx context.Context) error {
+			atomic.AddInt32(&called, 1)
+			if i%2 == 0 {
+				return errBad
+			}
+			return nil
+		}))
+	}
+	g.Join(ctx)
+	for i, h := range handles {
+		h.Join(ctx)
+		if err := h.Err(); (i%2 == 0) != (err == errBad) {
+			t.Errorf("Expected task %d to report its error, got %v", i, err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&called); n != 5 {
+		t.Errorf("Expected 5 tasks to run, got %d", n)
+	}
+	if n := len(g.Errors()); n != 3 {
+		t.Errorf("Expected 3 errors, got %d", n)
+	}
+	if !errors.Is(g.Err(), errBad) {
+		t.Errorf("Expected joined error to contain %v, got %v", errBad, g.Err())
+	}
+}
+
+func TestGroup_Cancel(t *testing.T) {
+	ctx := context.Background()
+	var g spawn.Group
+	for i := 0; i < 3; i++ {
+		g.Run(ctx, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	g.Cancel()
+	g.Join(ctx)
+
+	// Tasks started after Cancel start with a canceled context.
+	h := g.Run(ctx, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	h.Join(ctx)
+	if n := len(g.Errors()); n != 4 {
+		t.Errorf("Expected 4 errors, got %d", n)
+	}
+}
@@ -0,0 +1,59 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBusy is returned by Limiter.TryRun when the maximum number of tasks are in flight.
+var ErrBusy = errors.New("spawn: too many tasks in flight")
+
+// Limiter starts tasks like Run, but limits the number of tasks in flight. It is created by Limited.
+type Limiter struct {
+	sem chan struct{}
+}
+
+// Limited returns a Limiter that allows at most max tasks in flight.
+// It panics if max is not positive.
+func Limited(max int) *Limiter {
+	if max <= 0 {
+		panic("non-positive max for Limited")
+	}
+	return &Limiter{sem: make(chan struct{}, max)}
+}
+
+// Run starts a new concurrent task like Run, blocking while the maximum number of tasks are in flight.
+// If the context is canceled while waiting, f is not called, and the task completes as canceled with
+// the context error.
+func (l *Limiter) Run(ctx context.Context, f func(context.Context), options ...Option) Handle {
+	select {
+	case l.sem <- struct{}{}:
+		return l.run(ctx, f, options)
+	case <-ctx.Done():
+		return RunErr(ctx, func(ctx context.Context) error {
+			return ctx.Err()
+		}, options...)
+	}
+}
+
+// TryRun is like Run, but returns ErrBusy instead of blocking when the maximum number of tasks are in flight.
+func (l *Limiter) TryRun(ctx context.Context, f func(context.Context), options ...Option) (Handle, error) {
+	select {
+	case l.sem <- struct{}{}:
+		return l.run(ctx, f, options), nil
+	default:
+		return nil, ErrBusy
+	}
+}
+
+// InFlight returns the number of tasks in flight.
+func (l *Limiter) InFlight() int {
+	return len(l.sem)
+}
+
+func (l *Limiter) run(ctx context.Context, f func(context.Context), options []Option) Handle {
+	return Run(ctx, func(ctx context.Context) {
+		defer func() { <-l.sem }()
+		f(ctx)
+	}, options...)
+}
@@ -0,0 +1,57 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestLimited(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	limiter := spawn.Limited(2)
+
+	h1 := limiter.Run(ctx, func(ctx context.Context) { <-release })
+	h2, err := limiter.TryRun(ctx, func(ctx context.Context) { <-release })
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := limiter.InFlight(); n != 2 {
+		t.Errorf("Expected 2 tasks in flight, got %d", n)
+	}
+	if _, err := limiter.TryRun(ctx, func(ctx context.Context) {}); !errors.Is(err, spawn.ErrBusy) {
+		t.Errorf("Expected ErrBusy, got %v", err)
+	}
+
+	metrics := spawn.NewMetrics()
+	spawn.SetMetricsRecorder(metrics)
+	defer spawn.SetMetricsRecorder(nil)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	h := limiter.Run(timeout, func(ctx context.Context) {
+		t.Errorf("Expected task not to be started")
+	}, spawn.Named("limited-canceled"))
+	h.Join(ctx)
+	if !errors.Is(h.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", h.Err())
+	}
+	if s := h.State(); s != spawn.TaskCanceled {
+		t.Errorf("Expected canceled, got %v", s)
+	}
+	if s := h.Stats(); s.Started.IsZero() || s.Completed.IsZero() {
+		t.Errorf("Expected the stats of a completed task, got %+v", s)
+	}
+	if m := metrics.Snapshot()["limited-canceled"]; m.Started != 1 || m.Active != 0 {
+		t.Errorf("Expected the canceled task in the metrics, got %+v", m)
+	}
+
+	close(release)
+	h1.Join(ctx)
+	h2.Join(ctx)
+	if n := limiter.InFlight(); n != 0 {
+		t.Errorf("Expected no tasks in flight, got %d", n)
+	}
+}
@@ -0,0 +1,78 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// WithCollectAll makes Map process all items even if some fail, and return all errors joined
+// with errors.Join. By default, Map fails fast: the first error cancels the remaining items.
+// Passed to NewScope, it makes Scope.Wait return all errors instead of the first one.
+func WithCollectAll() Option {
+	return option(kindCollectAll, "WithCollectAll", func(o *taskOptions) {
+		o.collectAll = true
+	})
+}
+
+// Map calls f for each item with at most workers concurrent calls, and returns the results
+// in the order of the items.
+//
+// By default, the first error cancels the context passed to the other calls, skips the remaining
+// items, and is returned. With WithCollectAll, all items are processed and all errors are
+// returned joined; the results of failed items are the zero value. If the context is canceled,
+// the remaining items are skipped and the context error is returned.
+func Map[T, R any](ctx context.Context, items []T, workers int, f func(context.Context, T) (R, error), options ...Option) ([]R, error) {
+	if workers <= 0 {
+		panic("non-positive workers for Map")
+	}
+	var o taskOptions
+	o.apply("Map", kindCollectAll, options)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	var next atomic.Int64
+	var first error
+	var once sync.Once
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(items) {
+					return
+				}
+				r, err := f(ctx, items[i])
+				if err != nil {
+					errs[i] = err
+					if !o.collectAll {
+						once.Do(func() {
+							first = err
+							cancel()
+						})
+					}
+					continue
+				}
+				results[i] = r
+			}
+		}()
+	}
+	wg.Wait()
+
+	if o.collectAll {
+		if err := errors.Join(errs...); err != nil {
+			return results, err
+		}
+	} else if first != nil {
+		return results, first
+	}
+	if int(next.Load()) < len(items) {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
@@ -0,0 +1,65 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestMap(t *testing.T) {
+	ctx := context.Background()
+	items := []int{5, 1, 4, 2, 3}
+
+	got, err := spawn.Map(ctx, items, 3, func(ctx context.Context, x int) (string, error) {
+		time.Sleep(time.Duration(x) * time.Millisecond)
+		return strconv.Itoa(x * 10), nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"50", "10", "40", "20", "30"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestMapFailFast(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+	var called int32
+
+	_, err := spawn.Map(ctx, make([]int, 100), 2, func(ctx context.Context, x int) (int, error) {
+		if atomic.AddInt32(&called, 1) == 3 {
+			return 0, errBad
+		}
+		return x, nil
+	})
+	if !errors.Is(err, errBad) {
+		t.Errorf("Expected error %v, got %v", errBad, err)
+	}
+	if n := atomic.LoadInt32(&called); n >= 100 {
+		t.Errorf("Expected remaining items to be skipped, got %d calls", n)
+	}
+}
+
+func TestMapCollectAll(t *testing.T) {
+	ctx := context.Background()
+
+	got, err := spawn.Map(ctx, []int{1, 2, 3, 4}, 2, func(ctx context.Context, x int) (int, error) {
+		if x%2 == 0 {
+			return 0, errors.New("even " + strconv.Itoa(x))
+		}
+		return x, nil
+	}, spawn.WithCollectAll())
+	if err == nil || err.Error() != "even 2\neven 4" {
+		t.Errorf("Expected joined errors, got %v", err)
+	}
+	if want := []int{1, 0, 3, 0}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,62 @@
+package spawn
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge starts a new concurrent task that fans the values of the input channels into the returned
+// channel, in the order they are received. The returned channel is closed when all input channels
+// are closed or the task is canceled; values received from the inputs after cancellation are dropped.
+func Merge[T any](ctx context.Context, chs ...<-chan T) (<-chan T, Handle) {
+	out := make(chan T)
+	h := Run(ctx, func(ctx context.Context) {
+		defer close(out)
+		var wg sync.WaitGroup
+		for _, ch := range chs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				forward(ctx, ch, out)
+			}()
+		}
+		wg.Wait()
+	})
+	return out, h
+}
+
+// MergeOrdered is like Merge, but preserves the order of the input channels: all values of the
+// first channel are sent before the values of the second one, and so on. An input channel is not
+// read until all the previous ones are closed.
+func MergeOrdered[T any](ctx context.Context, chs ...<-chan T) (<-chan T, Handle) {
+	out := make(chan T)
+	h := Run(ctx, func(ctx context.Context) {
+		defer close(out)
+		for _, ch := range chs {
+			if !forward(ctx, ch, out) {
+				return
+			}
+		}
+	})
+	return out, h
+}
+
+// forward sends the values of in to out until in is closed, and reports whether it was closed
+// before the context was canceled.
+func forward[T any](ctx context.Context, in <-chan T, out chan<- T) bool {
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return true
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return false
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
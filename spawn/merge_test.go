@@ -0,0 +1,59 @@
+package spawn_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func feed(values ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, v := range values {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+	out, handle := spawn.Merge(ctx, feed(1, 2, 3), feed(4, 5), feed())
+
+	var got []int
+	for v := range out {
+		got = append(got, v)
+	}
+	handle.Join(ctx)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Expected [1 2 3 4 5], got %v", got)
+	}
+}
+
+func TestMergeOrdered(t *testing.T) {
+	ctx := context.Background()
+	out, _ := spawn.MergeOrdered(ctx, feed(1, 2, 3), feed(4, 5), feed(6))
+
+	var got []int
+	for v := range out {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Expected [1 2 3 4 5 6], got %v", got)
+	}
+}
+
+func TestMergeCancel(t *testing.T) {
+	ctx := context.Background()
+	never := make(chan int)
+	out, handle := spawn.Merge(ctx, never)
+
+	handle.Cancel()
+	if _, ok := <-out; ok {
+		t.Errorf("Expected output to be closed after cancel")
+	}
+}
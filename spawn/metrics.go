@@ -0,0 +1,169 @@
+package spawn
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsRecorder records the lifecycle events of tasks. Tasks are identified by the name given
+// with Named, or the empty string for unnamed tasks.
+type MetricsRecorder interface {
+	// Started records that a task started.
+	Started(name string)
+	// Stopped records that a task that ran for d stopped with err.
+	Stopped(name string, d time.Duration, err error)
+	// Panicked records that a task panicked with v. Only recovered panics are recorded: those that
+	// complete a task with WithRecover, and those after which WithRestartOnPanic resumes the task.
+	Panicked(name string, v any)
+	// Restarted records that a task was restarted after a failure, e.g. by RunRetry or WithRestartOnPanic.
+	Restarted(name string)
+}
+
+// SaturationRecorder is implemented by a MetricsRecorder that records the saturation of the channels
+// of Chan tasks, sampled with WithSaturation.
+type SaturationRecorder interface {
+	// Saturation records the sampled length and capacity of the channel at index of a task.
+	Saturation(name string, index, length, capacity int)
+}
+
+type noopRecorder struct{}
+
+func (noopRecorder) Started(string)                       {}
+func (noopRecorder) Stopped(string, time.Duration, error) {}
+func (noopRecorder) Panicked(string, any)                 {}
+func (noopRecorder) Restarted(string)                     {}
+
+type recorderHolder struct {
+	MetricsRecorder
+}
+
+var globalRecorder atomic.Pointer[recorderHolder]
+
+func init() {
+	globalRecorder.Store(&recorderHolder{noopRecorder{}})
+}
+
+// SetMetricsRecorder sets the global recorder of task lifecycle events. A nil recorder disables recording.
+func SetMetricsRecorder(r MetricsRecorder) {
+	if r == nil {
+		r = noopRecorder{}
+	}
+	globalRecorder.Store(&recorderHolder{r})
+}
+
+// GetMetricsRecorder returns the global recorder of task lifecycle events.
+func GetMetricsRecorder() MetricsRecorder {
+	return globalRecorder.Load().MetricsRecorder
+}
+
+// TaskMetrics is a snapshot of the metrics of tasks with the same name.
+type TaskMetrics struct {
+	// Active is the number of running tasks.
+	Active int64 `json:"active"`
+	// Started is the number of started tasks.
+	Started int64 `json:"started"`
+	// Errors is the number of tasks that stopped with an error, including panics.
+	Errors int64 `json:"errors"`
+	// Panics is the number of recovered panics.
+	Panics int64 `json:"panics"`
+	// Restarts is the number of restarts after failures.
+	Restarts int64 `json:"restarts"`
+	// Channels are the last sampled saturations of the channels of the tasks, by channel index.
+	Channels []ChannelSaturation `json:"channels,omitempty"`
+}
+
+// ChannelSaturation is a sample of the length and capacity of a channel.
+type ChannelSaturation struct {
+	Len int `json:"len"`
+	Cap int `json:"cap"`
+}
+
+// Metrics is a MetricsRecorder that keeps counts of task lifecycle events per task name.
+// It implements expvar.Var, so it can be published with expvar.Publish.
+type Metrics struct {
+	mu    sync.Mutex
+	tasks map[string]*TaskMetrics
+}
+
+// NewMetrics creates a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{tasks: make(map[string]*TaskMetrics)}
+}
+
+func (m *Metrics) update(name string, f func(*TaskMetrics)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tasks[name]
+	if !ok {
+		t = new(TaskMetrics)
+		m.tasks[name] = t
+	}
+	f(t)
+}
+
+// Started implements MetricsRecorder.
+func (m *Metrics) Started(name string) {
+	m.update(name, func(t *TaskMetrics) {
+		t.Active++
+		t.Started++
+	})
+}
+
+// Stopped implements MetricsRecorder.
+func (m *Metrics) Stopped(name string, d time.Duration, err error) {
+	m.update(name, func(t *TaskMetrics) {
+		t.Active--
+		if err != nil {
+			t.Errors++
+		}
+	})
+}
+
+// Panicked implements MetricsRecorder.
+func (m *Metrics) Panicked(name string, v any) {
+	m.update(name, func(t *TaskMetrics) {
+		t.Panics++
+	})
+}
+
+// Restarted implements MetricsRecorder.
+func (m *Metrics) Restarted(name string) {
+	m.update(name, func(t *TaskMetrics) {
+		t.Restarts++
+	})
+}
+
+// Saturation implements SaturationRecorder.
+func (m *Metrics) Saturation(name string, index, length, capacity int) {
+	m.update(name, func(t *TaskMetrics) {
+		if index >= len(t.Channels) {
+			t.Channels = append(t.Channels, make([]ChannelSaturation, index+1-len(t.Channels))...)
+		}
+		t.Channels[index] = ChannelSaturation{Len: length, Cap: capacity}
+	})
+}
+
+// Snapshot returns a copy of the metrics of all task names.
+func (m *Metrics) Snapshot() map[string]TaskMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]TaskMetrics, len(m.tasks))
+	for name, t := range m.tasks {
+		c := *t
+		c.Channels = slices.Clone(t.Channels)
+		snapshot[name] = c
+	}
+	return snapshot
+}
+
+// String returns the JSON representation of the metrics. It implements expvar.Var.
+func (m *Metrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
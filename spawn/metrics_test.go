@@ -0,0 +1,68 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+var _ expvar.Var = (*spawn.Metrics)(nil)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	metrics := spawn.NewMetrics()
+	spawn.SetMetricsRecorder(metrics)
+	defer spawn.SetMetricsRecorder(nil)
+
+	release := make(chan struct{})
+	h1 := spawn.Run(ctx, func(ctx context.Context) {
+		<-release
+	}, spawn.Named("test-metrics"))
+	if m := metrics.Snapshot()["test-metrics"]; m.Active != 1 || m.Started != 1 {
+		t.Errorf("Expected 1 active task, got %+v", m)
+	}
+
+	h2 := spawn.RunErr(ctx, func(ctx context.Context) error {
+		return errors.New("bad")
+	}, spawn.Named("test-metrics"))
+	h3 := spawn.Run(ctx, func(ctx context.Context) {
+		panic("boom")
+	}, spawn.Named("test-metrics"), spawn.WithRecover(func(any, []byte) {}))
+	close(release)
+	for _, h := range []spawn.Handle{h1, h2, h3} {
+		h.Join(ctx)
+	}
+
+	m := metrics.Snapshot()["test-metrics"]
+	if m.Active != 0 || m.Started != 3 || m.Errors != 2 || m.Panics != 1 {
+		t.Errorf("Expected 0 active, 3 started, 2 errors and 1 panic, got %+v", m)
+	}
+}
+
+func TestMetrics_RestartOnPanic(t *testing.T) {
+	ctx := context.Background()
+	metrics := spawn.NewMetrics()
+	spawn.SetMetricsRecorder(metrics)
+	defer spawn.SetMetricsRecorder(nil)
+
+	var called int
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		called++
+		if called <= 2 {
+			panic("boom")
+		}
+	}, time.Millisecond, spawn.Named("test-metrics-restart"), spawn.WithRestartOnPanic(time.Millisecond), spawn.MaxRuns(3))
+	handle.Join(ctx)
+
+	m := metrics.Snapshot()["test-metrics-restart"]
+	if m.Panics != 2 || m.Restarts != 2 || m.Errors != 0 {
+		t.Errorf("Expected 2 panics, 2 restarts and no errors, got %+v", m)
+	}
+	if handle.Panicked() {
+		t.Errorf("Expected task not to complete because of the panics")
+	}
+}
@@ -0,0 +1,135 @@
+package spawn
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// PipelineStage is a stage of a pipeline started by Pipeline. It is created by Stage.
+type PipelineStage struct {
+	in, out reflect.Type
+	workers int
+	buffer  int
+	// makeOut makes the output channel of the stage with the given buffer size.
+	makeOut func(buffer int) any
+	// run runs the stage function with the input and output channels.
+	run func(ctx context.Context, in, out any)
+	// closeOut closes the output channel.
+	closeOut func(out any)
+}
+
+// StageOption is a configuration option for the Stage function.
+type StageOption func(*PipelineStage)
+
+// WithWorkers sets the number of goroutines that run the stage function concurrently (default is 1).
+func WithWorkers(n int) StageOption {
+	if n <= 0 {
+		panic("non-positive workers for WithWorkers")
+	}
+	return func(s *PipelineStage) {
+		s.workers = n
+	}
+}
+
+// WithBuffer sets the buffer size of the output channel of the stage (default is 0).
+// It has no effect on the last stage, whose output channel is given to Pipeline.
+func WithBuffer(n int) StageOption {
+	if n < 0 {
+		panic("negative buffer for WithBuffer")
+	}
+	return func(s *PipelineStage) {
+		s.buffer = n
+	}
+}
+
+// Stage returns a PipelineStage that runs f. The function should read values from in until it is closed
+// or the context is canceled, write results to out, and return. It must not close out: the output
+// channel is closed by the pipeline once all goroutines of the stage have returned.
+func Stage[A, B any](f func(ctx context.Context, in <-chan A, out chan<- B), options ...StageOption) PipelineStage {
+	s := PipelineStage{
+		in:      reflect.TypeFor[A](),
+		out:     reflect.TypeFor[B](),
+		workers: 1,
+		makeOut: func(buffer int) any {
+			return make(chan B, buffer)
+		},
+		run: func(ctx context.Context, in, out any) {
+			f(ctx, recvChan[A](in), sendChan[B](out))
+		},
+		closeOut: func(out any) {
+			close(sendChan[B](out))
+		},
+	}
+	for _, opt := range options {
+		opt(&s)
+	}
+	return s
+}
+
+func recvChan[T any](ch any) <-chan T {
+	if c, ok := ch.(chan T); ok {
+		return c
+	}
+	return ch.(<-chan T)
+}
+
+func sendChan[T any](ch any) chan<- T {
+	if c, ok := ch.(chan T); ok {
+		return c
+	}
+	return ch.(chan<- T)
+}
+
+// Pipeline starts a pipeline that connects the stages with channels: the first stage reads from in,
+// each following stage reads the output of the previous one, and the last stage writes to out.
+// The output channel of each stage, including out, is closed once all goroutines of the stage
+// have returned, so the next stage sees the end of its input. The returned handle controls the whole
+// pipeline: canceling it cancels all stages, and it completes when all stages have returned.
+//
+// An error is returned if there are no stages or the element types of adjacent stages do not match.
+// The out channel is closed by the pipeline, so it must be the only writer of out.
+func Pipeline[T1, T2 any](ctx context.Context, in <-chan T1, out chan<- T2, stages ...PipelineStage) (Handle, error) {
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("spawn: no pipeline stages")
+	}
+	if t := reflect.TypeFor[T1](); stages[0].in != t {
+		return nil, fmt.Errorf("spawn: pipeline stage 0: input type %v does not match %v", stages[0].in, t)
+	}
+	for i := 1; i < len(stages); i++ {
+		if stages[i].in != stages[i-1].out {
+			return nil, fmt.Errorf("spawn: pipeline stage %d: input type %v does not match output type %v of stage %d", i, stages[i].in, stages[i-1].out, i-1)
+		}
+	}
+	if last := len(stages) - 1; stages[last].out != reflect.TypeFor[T2]() {
+		return nil, fmt.Errorf("spawn: pipeline stage %d: output type %v does not match %v", last, stages[last].out, reflect.TypeFor[T2]())
+	}
+
+	return Run(ctx, func(ctx context.Context) {
+		var wg sync.WaitGroup
+		var next any = in
+		for i, s := range stages {
+			src, dst := next, any(out)
+			if i < len(stages)-1 {
+				dst = s.makeOut(s.buffer)
+			}
+			var workers sync.WaitGroup
+			workers.Add(s.workers)
+			for range s.workers {
+				go func() {
+					defer workers.Done()
+					s.run(ctx, src, dst)
+				}()
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				workers.Wait()
+				s.closeOut(dst)
+			}()
+			next = dst
+		}
+		wg.Wait()
+	}), nil
+}
@@ -0,0 +1,90 @@
+package spawn_test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := make(chan string)
+
+	double := spawn.Stage(func(ctx context.Context, in <-chan int, out chan<- int) {
+		for x := range in {
+			select {
+			case out <- x * 2:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, spawn.WithWorkers(3), spawn.WithBuffer(4))
+	format := spawn.Stage(func(ctx context.Context, in <-chan int, out chan<- string) {
+		for x := range in {
+			select {
+			case out <- strconv.Itoa(x):
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	handle, err := spawn.Pipeline(ctx, in, out, double, format)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	go func() {
+		for i := range 5 {
+			in <- i
+		}
+		close(in)
+	}()
+
+	var got []string
+	for s := range out {
+		got = append(got, s)
+	}
+	handle.Join(ctx)
+
+	slices.Sort(got)
+	if want := []string{"0", "2", "4", "6", "8"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := make(chan int)
+
+	forward := spawn.Stage(func(ctx context.Context, in <-chan int, out chan<- int) {
+		<-ctx.Done()
+	})
+	handle, err := spawn.Pipeline(ctx, in, out, forward, forward)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	handle.Cancel()
+	handle.Join(ctx)
+	if _, ok := <-out; ok {
+		t.Errorf("Expected output channel to be closed")
+	}
+}
+
+func TestPipelineTypeMismatch(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := make(chan int)
+
+	format := spawn.Stage(func(ctx context.Context, in <-chan int, out chan<- string) {})
+	if _, err := spawn.Pipeline(ctx, in, out, format); err == nil {
+		t.Errorf("Expected type mismatch error")
+	}
+	if _, err := spawn.Pipeline[int, int](ctx, in, out); err == nil {
+		t.Errorf("Expected error for no stages")
+	}
+}
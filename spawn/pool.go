@@ -0,0 +1,117 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting a task to a WorkerPool that has been closed or canceled.
+	ErrPoolClosed = errors.New("spawn: pool closed")
+	// ErrPoolFull is returned by TrySubmit when the queue of a WorkerPool is full.
+	ErrPoolFull = errors.New("spawn: pool queue full")
+)
+
+// WorkerPool runs submitted tasks on a fixed number of worker goroutines, buffering up to a fixed
+// number of pending tasks. It is created by Pool.
+//
+// Close stops accepting tasks and lets the workers drain the queue; Cancel stops the workers
+// without running the remaining queued tasks. Join waits for all workers to exit.
+type WorkerPool struct {
+	Handle
+	mu     sync.RWMutex
+	closed bool
+	tasks  chan func(context.Context)
+	ctx    context.Context
+}
+
+// Pool starts a pool of workers goroutines that run submitted tasks with a queue of queue pending tasks.
+// Tasks receive a context derived from ctx that is canceled when the pool is canceled.
+// It panics if workers is not positive or queue is negative.
+func Pool(ctx context.Context, workers, queue int) *WorkerPool {
+	if workers <= 0 {
+		panic("non-positive workers for Pool")
+	}
+	if queue < 0 {
+		panic("negative queue for Pool")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &WorkerPool{
+		tasks: make(chan func(context.Context), queue),
+		ctx:   ctx,
+	}
+	p.Handle = Run(ctx, func(ctx context.Context) {
+		defer cancel()
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for range workers {
+			go func() {
+				defer wg.Done()
+				p.work(ctx)
+			}()
+		}
+		wg.Wait()
+	})
+	return p
+}
+
+// work runs queued tasks until the queue is closed and drained or the context is canceled.
+func (p *WorkerPool) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case f, ok := <-p.tasks:
+			if !ok {
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			f(ctx)
+		}
+	}
+}
+
+// Submit queues the task f, blocking while the queue is full.
+// It returns ErrPoolClosed if the pool has been closed or canceled.
+func (p *WorkerPool) Submit(f func(context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed || p.ctx.Err() != nil {
+		return ErrPoolClosed
+	}
+	select {
+	case p.tasks <- f:
+		return nil
+	case <-p.ctx.Done():
+		return ErrPoolClosed
+	}
+}
+
+// TrySubmit is like Submit, but returns ErrPoolFull instead of blocking when the queue is full.
+func (p *WorkerPool) TrySubmit(f func(context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed || p.ctx.Err() != nil {
+		return ErrPoolClosed
+	}
+	select {
+	case p.tasks <- f:
+		return nil
+	default:
+		return ErrPoolFull
+	}
+}
+
+// Close stops the pool from accepting new tasks. Queued tasks are still run; use Join to wait
+// for them to complete. Close blocks while a Submit call is waiting for space in the queue.
+func (p *WorkerPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+}
@@ -0,0 +1,71 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+	var running, peak int32
+
+	pool := spawn.Pool(ctx, 2, 10)
+	for range 10 {
+		if err := pool.Submit(func(ctx context.Context) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&called, 1)
+			atomic.AddInt32(&running, -1)
+		}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	pool.Close()
+	pool.Join(ctx)
+
+	if n := atomic.LoadInt32(&called); n != 10 {
+		t.Errorf("Expected all 10 queued tasks to run, got %d", n)
+	}
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got %d", p)
+	}
+	if err := pool.Submit(func(ctx context.Context) {}); !errors.Is(err, spawn.ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
+
+func TestPoolTrySubmit(t *testing.T) {
+	ctx := context.Background()
+	block := make(chan struct{})
+	started := make(chan struct{})
+
+	pool := spawn.Pool(ctx, 1, 1)
+	pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-block
+	})
+	<-started
+	if err := pool.TrySubmit(func(ctx context.Context) {}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := pool.TrySubmit(func(ctx context.Context) {}); !errors.Is(err, spawn.ErrPoolFull) {
+		t.Errorf("Expected ErrPoolFull, got %v", err)
+	}
+
+	pool.Cancel()
+	close(block)
+	pool.Join(ctx)
+	if err := pool.TrySubmit(func(ctx context.Context) {}); !errors.Is(err, spawn.ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package spawn
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Named names the task and registers it in the global task registry while it is running,
+// so that it is listed by Tasks. Unnamed tasks are not registered.
+func Named(name string) Option {
+	if name == "" {
+		panic("empty name for Named")
+	}
+	return option(kindTask, "Named", func(o *taskOptions) {
+		o.name = name
+	})
+}
+
+// TaskInfo describes a running named task.
+type TaskInfo struct {
+	ID      uint64    `json:"id"`
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+	State   TaskState `json:"state"`
+}
+
+// registry is the global registry of running named tasks.
+var registry = &taskRegistry{tasks: make(map[uint64]*registeredTask)}
+
+type registeredTask struct {
+	name    string
+	started time.Time
+	handle  *taskHandle
+}
+
+type taskRegistry struct {
+	mu     sync.Mutex
+	nextID uint64
+	tasks  map[uint64]*registeredTask
+}
+
+func (r *taskRegistry) add(name string, h *taskHandle) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.tasks[r.nextID] = &registeredTask{name: name, started: time.Now(), handle: h}
+	return r.nextID
+}
+
+func (r *taskRegistry) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tasks, id)
+}
+
+// Tasks returns a snapshot of the running named tasks, ordered by start.
+func Tasks() []TaskInfo {
+	registry.mu.Lock()
+	tasks := make([]TaskInfo, 0, len(registry.tasks))
+	for id, t := range registry.tasks {
+		tasks = append(tasks, TaskInfo{ID: id, Name: t.name, Started: t.started, State: t.handle.State()})
+	}
+	registry.mu.Unlock()
+	slices.SortFunc(tasks, func(a, b TaskInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return tasks
+}
+
+// TasksHandler returns an HTTP handler that writes the result of Tasks as JSON.
+// To publish the tasks with expvar instead, use:
+//
+//	expvar.Publish("tasks", expvar.Func(func() any { return spawn.Tasks() }))
+func TasksHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Tasks())
+	})
+}
@@ -0,0 +1,63 @@
+package spawn_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func findTask(tasks []spawn.TaskInfo, name string) (spawn.TaskInfo, bool) {
+	for _, t := range tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return spawn.TaskInfo{}, false
+}
+
+func TestTasks(t *testing.T) {
+	ctx := context.Background()
+	started, release := make(chan struct{}), make(chan struct{})
+
+	handle := spawn.Run(ctx, func(ctx context.Context) {
+		close(started)
+		<-release
+	}, spawn.Named("test-registry"))
+	// The task is registered as soon as it is started, pending until its goroutine runs.
+	if info, ok := findTask(spawn.Tasks(), "test-registry"); !ok || (info.State != spawn.TaskPending && info.State != spawn.TaskRunning) {
+		t.Errorf("Expected pending or running task, got %+v", info)
+	}
+	<-started
+
+	info, ok := findTask(spawn.Tasks(), "test-registry")
+	if !ok {
+		t.Fatalf("Expected task to be registered")
+	}
+	if info.State != spawn.TaskRunning || info.Started.IsZero() {
+		t.Errorf("Expected running task with start time, got %+v", info)
+	}
+
+	handle.Cancel()
+	if info, _ := findTask(spawn.Tasks(), "test-registry"); info.State != spawn.TaskCanceling {
+		t.Errorf("Expected canceling task, got %v", info.State)
+	}
+
+	rec := httptest.NewRecorder()
+	spawn.TasksHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	var tasks []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("Expected JSON response, got %v", err)
+	}
+	if len(tasks) == 0 || tasks[0]["state"] != "canceling" {
+		t.Errorf("Expected canceling task in response, got %s", rec.Body.String())
+	}
+
+	close(release)
+	handle.Join(ctx)
+	if _, ok := findTask(spawn.Tasks(), "test-registry"); ok {
+		t.Errorf("Expected task to be unregistered")
+	}
+}
@@ -0,0 +1,70 @@
+package spawn
+
+import (
+	"context"
+	"time"
+)
+
+// Default backoff delays of RunRetry.
+const (
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// MaxAttempts sets the maximum number of attempts of RunRetry, including the first one
+// (default is 0, which means unlimited).
+func MaxAttempts(n int) Option {
+	if n < 0 {
+		panic("negative attempts for MaxAttempts")
+	}
+	return option(kindRetry, "MaxAttempts", func(o *taskOptions) {
+		o.maxAttempts = n
+	})
+}
+
+// Backoff sets the delay before the first retry of RunRetry and the maximum delay. The delay doubles
+// after each retry up to the maximum (default is DefaultMinBackoff and DefaultMaxBackoff).
+func Backoff(min, max time.Duration) Option {
+	if min <= 0 || max < min {
+		panic("invalid delays for Backoff")
+	}
+	return option(kindRetry, "Backoff", func(o *taskOptions) {
+		o.minBackoff = min
+		o.maxBackoff = max
+	})
+}
+
+// RunRetry starts a new concurrent task that calls f until it returns nil, the maximum number of attempts
+// set by MaxAttempts is reached, or the context is canceled, waiting with exponential backoff between
+// attempts. The error of the last attempt is reported by the Err method of the handle.
+// Each retry is recorded as a restart by the global MetricsRecorder. A panic recovered by WithRecover
+// is not retried.
+func RunRetry(ctx context.Context, f func(context.Context) error, options ...Option) Handle {
+	t := &task{taskOptions: taskOptions{
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}}
+	t.apply("RunRetry", kindTask|kindRetry, options)
+	t.periodic = true
+	recorder := GetMetricsRecorder()
+	return start(ctx, t, func(ctx context.Context) error {
+		delay := t.minBackoff
+		for attempt := 1; ; attempt++ {
+			began := time.Now()
+			err := f(ctx)
+			t.handle.record(began, err)
+			if err == nil || attempt == t.maxAttempts || ctx.Err() != nil {
+				return err
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+			delay = min(delay*2, t.maxBackoff)
+			recorder.Restarted(t.name)
+		}
+	})
+}
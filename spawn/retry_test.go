@@ -0,0 +1,59 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestRunRetry(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+	metrics := spawn.NewMetrics()
+	spawn.SetMetricsRecorder(metrics)
+	defer spawn.SetMetricsRecorder(nil)
+
+	var attempts int
+	handle := spawn.RunRetry(ctx, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errBad
+		}
+		return nil
+	}, spawn.Backoff(time.Millisecond, 10*time.Millisecond), spawn.Named("test-retry"))
+	handle.Join(ctx)
+	if attempts != 3 || handle.Err() != nil {
+		t.Errorf("Expected success after 3 attempts, got %d attempts and error %v", attempts, handle.Err())
+	}
+	if m := metrics.Snapshot()["test-retry"]; m.Restarts != 2 {
+		t.Errorf("Expected 2 restarts, got %d", m.Restarts)
+	}
+
+	attempts = 0
+	handle = spawn.RunRetry(ctx, func(ctx context.Context) error {
+		attempts++
+		return errBad
+	}, spawn.MaxAttempts(4), spawn.Backoff(time.Millisecond, time.Millisecond))
+	handle.Join(ctx)
+	if attempts != 4 || !errors.Is(handle.Err(), errBad) {
+		t.Errorf("Expected 4 failed attempts, got %d attempts and error %v", attempts, handle.Err())
+	}
+}
+
+func TestRunRetryCancel(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+
+	handle := spawn.RunRetry(ctx, func(ctx context.Context) error {
+		return errBad
+	}, spawn.Backoff(time.Hour, time.Hour))
+	time.Sleep(10 * time.Millisecond)
+	handle.Cancel()
+	handle.Join(ctx)
+	if !errors.Is(handle.Err(), errBad) {
+		t.Errorf("Expected last error %v, got %v", errBad, handle.Err())
+	}
+}
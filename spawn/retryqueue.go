@@ -0,0 +1,201 @@
+package spawn
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrQueueStopped is returned when adding an item to a RetryQueue that has stopped.
+var ErrQueueStopped = errors.New("spawn: retry queue stopped")
+
+// RetryError is the error reported to the Collector of a RetryQueue, set with WithCollector,
+// for an item that failed its maximum number of attempts.
+type RetryError[T any] struct {
+	// Item is the item that failed.
+	Item T
+	// Attempts is the number of attempts.
+	Attempts int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Error returns the error message.
+func (e *RetryError[T]) Error() string {
+	return "spawn: item failed after " + strconv.Itoa(e.Attempts) + " attempts: " + e.Err.Error()
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *RetryError[T]) Unwrap() error {
+	return e.Err
+}
+
+// RetryQueue calls a handler for each item when it is due, and re-enqueues the items whose handler
+// fails with exponential backoff, like RunRetry. It is useful for webhook delivery and message
+// reprocessing loops. It is created by NewRetryQueue.
+//
+// The options MaxAttempts and Backoff set the retry policy of each item. An item that fails its
+// maximum number of attempts is dropped and reported as a *RetryError to the Collector set with
+// WithCollector. The Stats of the handle count the calls to the handler.
+type RetryQueue[T any] struct {
+	Handle
+	t       *task
+	handler func(context.Context, T) error
+
+	mu      sync.Mutex
+	items   retryHeap[T]
+	stopped bool
+	wake    chan struct{}
+}
+
+type retryItem[T any] struct {
+	value    T
+	due      time.Time
+	attempts int
+}
+
+// retryHeap is a min-heap of items ordered by due time.
+type retryHeap[T any] []*retryItem[T]
+
+func (h retryHeap[T]) Len() int           { return len(h) }
+func (h retryHeap[T]) Less(i, j int) bool { return h[i].due.Before(h[j].due) }
+func (h retryHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *retryHeap[T]) Push(x any)        { *h = append(*h, x.(*retryItem[T])) }
+func (h *retryHeap[T]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// NewRetryQueue starts a RetryQueue that calls handler for due items on workers goroutines.
+// Pending items are dropped when the queue is canceled. It panics if workers is not positive.
+func NewRetryQueue[T any](ctx context.Context, workers int, handler func(context.Context, T) error, options ...Option) *RetryQueue[T] {
+	if workers <= 0 {
+		panic("non-positive workers for NewRetryQueue")
+	}
+	if handler == nil {
+		panic("nil handler for NewRetryQueue")
+	}
+	q := &RetryQueue[T]{
+		t: &task{taskOptions: taskOptions{
+			minBackoff: DefaultMinBackoff,
+			maxBackoff: DefaultMaxBackoff,
+		}},
+		handler: handler,
+		wake:    make(chan struct{}, 1),
+	}
+	q.t.apply("NewRetryQueue", kindTask|kindRetry, options)
+	q.t.periodic = true
+	q.Handle = start(ctx, q.t, func(ctx context.Context) error {
+		defer q.stop()
+		pool := Pool(ctx, workers, 0)
+		defer pool.Join(context.Background())
+		defer pool.Cancel()
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			for _, item := range q.due() {
+				if pool.Submit(func(ctx context.Context) { q.handle(ctx, item) }) != nil {
+					return nil
+				}
+			}
+			if d, ok := q.next(); ok {
+				timer.Reset(d)
+			}
+			select {
+			case <-timer.C:
+			case <-q.wake:
+			case <-ctx.Done():
+				return nil
+			}
+			timer.Stop()
+		}
+	})
+	return q
+}
+
+// Add enqueues the item to be handled after the delay.
+// It returns ErrQueueStopped if the queue has stopped.
+func (q *RetryQueue[T]) Add(item T, delay time.Duration) error {
+	return q.push(&retryItem[T]{value: item, due: time.Now().Add(delay)})
+}
+
+// Len returns the number of items waiting to be due.
+func (q *RetryQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+func (q *RetryQueue[T]) push(item *retryItem[T]) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return ErrQueueStopped
+	}
+	heap.Push(&q.items, item)
+	if q.items[0] == item {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// due removes and returns the items that are due.
+func (q *RetryQueue[T]) due() []*retryItem[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var items []*retryItem[T]
+	now := time.Now()
+	for len(q.items) > 0 && !q.items[0].due.After(now) {
+		items = append(items, heap.Pop(&q.items).(*retryItem[T]))
+	}
+	return items
+}
+
+// next returns the duration until the next item is due, if any.
+func (q *RetryQueue[T]) next() (time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return 0, false
+	}
+	return time.Until(q.items[0].due), true
+}
+
+func (q *RetryQueue[T]) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.items = nil
+}
+
+// handle calls the handler for the item, and re-enqueues it with backoff if it fails.
+func (q *RetryQueue[T]) handle(ctx context.Context, item *retryItem[T]) {
+	began := time.Now()
+	err := q.handler(ctx, item.value)
+	q.t.handle.record(began, err)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	item.attempts++
+	if item.attempts == q.t.maxAttempts {
+		if q.t.collector != nil {
+			q.t.collector.report(ctx, q.t.name, &RetryError[T]{Item: item.value, Attempts: item.attempts, Err: err})
+		}
+		return
+	}
+	delay := q.t.minBackoff << (item.attempts - 1)
+	if delay <= 0 || delay > q.t.maxBackoff {
+		delay = q.t.maxBackoff
+	}
+	item.due = time.Now().Add(delay)
+	q.push(item)
+}
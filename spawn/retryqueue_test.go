@@ -0,0 +1,66 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestRetryQueue(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	collector := spawn.NewCollector(1)
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	delivered := make(chan string, 1)
+
+	q := spawn.NewRetryQueue(ctx, 2, func(ctx context.Context, item string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[item]++
+		if item == "ok" && attempts[item] == 3 {
+			delivered <- item
+			return nil
+		}
+		return errFailed
+	}, spawn.MaxAttempts(3), spawn.Backoff(time.Millisecond, 5*time.Millisecond), spawn.WithCollector(collector))
+	defer q.Cancel()
+
+	start := time.Now()
+	q.Add("ok", 20*time.Millisecond)
+	q.Add("bad", 0)
+
+	select {
+	case e := <-collector.C():
+		var re *spawn.RetryError[string]
+		if !errors.As(e, &re) || re.Item != "bad" || re.Attempts != 3 || !errors.Is(e, errFailed) {
+			t.Errorf("Expected bad to fail after 3 attempts, got %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected bad to be reported")
+	}
+	select {
+	case <-delivered:
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("Expected ok to be delivered after its delay, got %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected ok to be delivered")
+	}
+	if n := q.Len(); n != 0 {
+		t.Errorf("Expected empty queue, got %d items", n)
+	}
+
+	q.Cancel()
+	q.Join(ctx)
+	if runs := q.Stats().Runs; runs != 6 {
+		t.Errorf("Expected 6 handler calls, got %d", runs)
+	}
+	if err := q.Add("late", 0); err != spawn.ErrQueueStopped {
+		t.Errorf("Expected ErrQueueStopped, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package spawn
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	wheelBits   = 6
+	wheelSize   = 1 << wheelBits
+	wheelMask   = wheelSize - 1
+	wheelLevels = 4
+
+	// schedulerQueue is the number of due callbacks a Scheduler buffers for its workers.
+	schedulerQueue = 1024
+)
+
+// Scheduler multiplexes many timers onto a few goroutines with a hierarchical timer wheel. It is
+// created by NewScheduler, and is an alternative to After and Tick when tens of thousands of timers
+// are needed: a timer costs one small allocation instead of a goroutine and a runtime timer.
+//
+// Timers have the resolution of the Scheduler: they fire on the first tick of the wheel at or after
+// their due time. With 4 levels of 64 slots, timers due within 2^24 ticks are placed directly; later
+// timers are cascaded until they are due.
+//
+// The Handle of a Scheduler is the task that advances the wheel. Canceling it stops all timers.
+type Scheduler struct {
+	Handle
+	resolution time.Duration
+	pool       *WorkerPool
+	started    time.Time
+
+	mu     sync.Mutex
+	now    int64
+	wheels [wheelLevels][wheelSize][]*Timer
+}
+
+// Timer is a timer of a Scheduler, created by its After and Tick methods.
+type Timer struct {
+	s      *Scheduler
+	f      func(context.Context)
+	due    int64
+	period int64
+	state  atomic.Int32
+}
+
+const (
+	timerPending int32 = iota
+	timerFired
+	timerStopped
+)
+
+// NewScheduler starts a Scheduler that advances its wheel every resolution, and runs the callbacks
+// of due timers on workers goroutines. It panics if resolution or workers is not positive.
+func NewScheduler(ctx context.Context, resolution time.Duration, workers int, options ...Option) *Scheduler {
+	if resolution <= 0 {
+		panic("non-positive resolution for NewScheduler")
+	}
+	if workers <= 0 {
+		panic("non-positive workers for NewScheduler")
+	}
+	s := &Scheduler{
+		resolution: resolution,
+		started:    time.Now(),
+	}
+	s.Handle = Run(ctx, func(ctx context.Context) {
+		s.pool = Pool(ctx, workers, schedulerQueue)
+		defer s.pool.Join(context.Background())
+		defer s.pool.Cancel()
+		ticker := time.NewTicker(resolution)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.advance(int64(time.Since(s.started) / resolution))
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, options...)
+	return s
+}
+
+// After schedules f to be called once after the duration d.
+func (s *Scheduler) After(d time.Duration, f func(context.Context)) *Timer {
+	return s.schedule(d, 0, f)
+}
+
+// Tick schedules f to be called every period d, starting after d. Calls do not drift: each is
+// scheduled one period after the due time of the previous one. It panics if d is not positive.
+func (s *Scheduler) Tick(d time.Duration, f func(context.Context)) *Timer {
+	if d <= 0 {
+		panic("non-positive interval for Scheduler.Tick")
+	}
+	return s.schedule(d, max(1, s.ticks(d)), f)
+}
+
+// Stop prevents the timer from firing again. It returns true if the call stops the timer,
+// or false if the timer has already fired once (for After) or been stopped. A stopped timer stays
+// in the wheel, and is released when the wheel reaches its slot.
+func (t *Timer) Stop() bool {
+	if t.period > 0 {
+		return t.state.Swap(timerStopped) != timerStopped
+	}
+	return t.state.CompareAndSwap(timerPending, timerStopped)
+}
+
+// ticks converts a duration to a number of ticks, rounding up.
+func (s *Scheduler) ticks(d time.Duration) int64 {
+	return int64((d + s.resolution - 1) / s.resolution)
+}
+
+func (s *Scheduler) schedule(d time.Duration, period int64, f func(context.Context)) *Timer {
+	if f == nil {
+		panic("nil function for Scheduler")
+	}
+	t := &Timer{s: s, f: f, period: period}
+	due := s.ticks(time.Since(s.started) + d)
+	s.mu.Lock()
+	t.due = max(due, s.now+1)
+	s.add(t)
+	s.mu.Unlock()
+	return t
+}
+
+// add places the timer in the wheel according to its due tick. The mutex must be held.
+func (s *Scheduler) add(t *Timer) {
+	delta := max(t.due-s.now, 0)
+	for level := range wheelLevels {
+		if delta < 1<<(wheelBits*(level+1)) || level == wheelLevels-1 {
+			due := t.due
+			if level == wheelLevels-1 {
+				due = s.now + min(delta, 1<<(wheelBits*wheelLevels)-1)
+			}
+			slot := &s.wheels[level][(due>>(wheelBits*level))&wheelMask]
+			*slot = append(*slot, t)
+			return
+		}
+	}
+}
+
+// advance processes all ticks up to and including the tick to.
+func (s *Scheduler) advance(to int64) {
+	var due []*Timer
+	s.mu.Lock()
+	for s.now < to {
+		s.now++
+		for level := 1; level < wheelLevels && s.now&(1<<(wheelBits*level)-1) == 0; level++ {
+			slot := &s.wheels[level][(s.now>>(wheelBits*level))&wheelMask]
+			timers := *slot
+			*slot = nil
+			for _, t := range timers {
+				if t.state.Load() != timerStopped {
+					s.add(t)
+				}
+			}
+		}
+		slot := &s.wheels[0][s.now&wheelMask]
+		timers := *slot
+		*slot = nil
+		for _, t := range timers {
+			if t.period > 0 {
+				if t.state.Load() == timerStopped {
+					continue
+				}
+				t.due += t.period
+				s.add(t)
+			} else if !t.state.CompareAndSwap(timerPending, timerFired) {
+				continue
+			}
+			due = append(due, t)
+		}
+	}
+	s.mu.Unlock()
+	for _, t := range due {
+		if s.pool.Submit(t.f) != nil {
+			return
+		}
+	}
+}
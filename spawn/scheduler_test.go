@@ -0,0 +1,119 @@
+package spawn_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	s := spawn.NewScheduler(ctx, time.Millisecond, 2)
+	defer s.Cancel()
+
+	fired := make(chan time.Duration, 3)
+	start := time.Now()
+	for _, d := range []time.Duration{5 * time.Millisecond, 100 * time.Millisecond} {
+		s.After(d, func(ctx context.Context) {
+			fired <- time.Since(start)
+		})
+	}
+	stopped := s.After(20*time.Millisecond, func(ctx context.Context) {
+		fired <- 0
+	})
+	if !stopped.Stop() {
+		t.Errorf("Expected Stop to stop a pending timer")
+	}
+	for _, d := range []time.Duration{5 * time.Millisecond, 100 * time.Millisecond} {
+		select {
+		case elapsed := <-fired:
+			if elapsed < d {
+				t.Errorf("Expected timer to fire after %v, got %v", d, elapsed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected timer of %v to fire", d)
+		}
+	}
+	select {
+	case <-fired:
+		t.Errorf("Expected stopped timer not to fire")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSchedulerCascade(t *testing.T) {
+	ctx := context.Background()
+	s := spawn.NewScheduler(ctx, 50*time.Microsecond, 1)
+	defer s.Cancel()
+
+	// 6000 ticks are beyond the first two levels of the wheel.
+	d := 300 * time.Millisecond
+	fired := make(chan time.Duration, 1)
+	start := time.Now()
+	s.After(d, func(ctx context.Context) {
+		fired <- time.Since(start)
+	})
+	select {
+	case elapsed := <-fired:
+		if elapsed < d {
+			t.Errorf("Expected timer to fire after %v, got %v", d, elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("Expected timer to fire")
+	}
+}
+
+func TestSchedulerTick(t *testing.T) {
+	ctx := context.Background()
+	s := spawn.NewScheduler(ctx, time.Millisecond, 1)
+	var count atomic.Int32
+
+	timer := s.Tick(10*time.Millisecond, func(ctx context.Context) {
+		count.Add(1)
+	})
+	time.Sleep(55 * time.Millisecond)
+	timer.Stop()
+	n := count.Load()
+	if n < 3 || n > 6 {
+		t.Errorf("Expected about 5 ticks, got %d", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if count.Load() != n {
+		t.Errorf("Expected no ticks after Stop")
+	}
+	s.Cancel()
+	s.Join(ctx)
+}
+
+func BenchmarkSchedulerAfter(b *testing.B) {
+	ctx := context.Background()
+	s := spawn.NewScheduler(ctx, time.Millisecond, 4)
+	defer s.Cancel()
+	f := func(ctx context.Context) {}
+	timers := make([]*spawn.Timer, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		timers[i] = s.After(time.Hour, f)
+	}
+	for _, t := range timers {
+		t.Stop()
+	}
+}
+
+func BenchmarkAfter(b *testing.B) {
+	ctx := context.Background()
+	f := func(ctx context.Context) {}
+	handles := make([]spawn.Handle, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		handles[i] = spawn.After(ctx, time.Hour, f)
+	}
+	for _, h := range handles {
+		h.Cancel()
+	}
+}
@@ -0,0 +1,133 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Scope tracks a tree of tasks. Tasks started with the Run method of a Scope are its children,
+// and the context passed to each task carries a child Scope, so that tasks started from within
+// a task with ScopeFromContext(ctx).Run are its descendants. Canceling a Scope cancels all of its
+// descendants, and a task does not complete until all of its descendants have completed, so
+// joining a Scope waits for the whole tree.
+//
+// A Scope is useful for request-scoped background work that must not outlive the request.
+//
+// Tasks started with the Go method return an error, like in errgroup: by default the first error
+// cancels the Scope and is returned by Wait. With the WithCollectAll option, errors do not cancel
+// the Scope and Wait returns all of them.
+type Scope struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	collectAll bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+type scopeKey struct{}
+
+// NewScope creates a new Scope with a context derived from ctx.
+// The WithCollectAll option sets the error policy of the Scope; it is the only option accepted.
+func NewScope(ctx context.Context, options ...Option) *Scope {
+	var o taskOptions
+	o.apply("NewScope", kindCollectAll, options)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Scope{cancel: cancel, collectAll: o.collectAll}
+	s.ctx = context.WithValue(ctx, scopeKey{}, s)
+	return s
+}
+
+// ScopeFromContext returns the Scope carried by the context, or nil if there is none.
+func ScopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// Context returns the context of the Scope, which carries the Scope and is canceled by Cancel.
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Run starts a new concurrent task in the Scope. The context passed to f carries a child Scope
+// of the task. The returned handle completes when f and all tasks started in the child Scope
+// have returned.
+func (s *Scope) Run(f func(context.Context), options ...Option) Handle {
+	s.wg.Add(1)
+	return Run(s.ctx, func(ctx context.Context) {
+		defer s.wg.Done()
+		child := NewScope(ctx)
+		defer child.wait()
+		f(child.ctx)
+	}, options...)
+}
+
+// Go starts a new concurrent task in the Scope like Run, for a function that returns an error.
+// The error is recorded by the Scope according to its error policy. If the task panics and
+// WithRecover is given, the resulting *PanicError is recorded like an error.
+func (s *Scope) Go(f func(context.Context) error, options ...Option) Handle {
+	t := newTask("Go", kindTask, options)
+	t.exited = func(err error) {
+		if err != nil {
+			s.fail(err)
+		}
+		s.wg.Done()
+	}
+	s.wg.Add(1)
+	return start(s.ctx, t, func(ctx context.Context) error {
+		child := NewScope(ctx)
+		defer child.wait()
+		return f(child.ctx)
+	})
+}
+
+// Wait waits for all tasks in the Scope and their descendants to complete, and returns the error
+// of the first failed task started with Go, or all of them joined with errors.Join under WithCollectAll.
+func (s *Scope) Wait() error {
+	s.wg.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.collectAll {
+		return errors.Join(s.errs...)
+	}
+	if len(s.errs) > 0 {
+		return s.errs[0]
+	}
+	return nil
+}
+
+// fail records the error of a task and cancels the Scope unless all errors are collected.
+func (s *Scope) fail(err error) {
+	s.mu.Lock()
+	s.errs = append(s.errs, err)
+	s.mu.Unlock()
+	if !s.collectAll {
+		s.cancel()
+	}
+}
+
+// wait waits for all tasks in the Scope to complete and releases the context of the Scope.
+func (s *Scope) wait() {
+	s.wg.Wait()
+	s.cancel()
+}
+
+// Join waits for all tasks in the Scope and their descendants to complete or the context to be canceled.
+func (s *Scope) Join(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-ctx.Done():
+	case <-done:
+	}
+}
+
+// Cancel cancels all tasks in the Scope and their descendants.
+func (s *Scope) Cancel() {
+	s.cancel()
+}
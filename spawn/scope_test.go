@@ -0,0 +1,102 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestScope(t *testing.T) {
+	ctx := context.Background()
+	var finished int32
+
+	scope := spawn.NewScope(ctx)
+	parent := scope.Run(func(ctx context.Context) {
+		for range 3 {
+			spawn.ScopeFromContext(ctx).Run(func(ctx context.Context) {
+				spawn.ScopeFromContext(ctx).Run(func(ctx context.Context) {
+					<-ctx.Done()
+					atomic.AddInt32(&finished, 1)
+				})
+				<-ctx.Done()
+				atomic.AddInt32(&finished, 1)
+			})
+		}
+	})
+
+	select {
+	case <-parent.Done():
+		t.Fatalf("Expected parent task to wait for its descendants")
+	default:
+	}
+
+	scope.Cancel()
+	scope.Join(ctx)
+	if n := atomic.LoadInt32(&finished); n != 6 {
+		t.Errorf("Expected 6 descendants to finish, got %d", n)
+	}
+	parent.Join(ctx)
+}
+
+func TestScopeFromContext(t *testing.T) {
+	if spawn.ScopeFromContext(context.Background()) != nil {
+		t.Errorf("Expected no scope in background context")
+	}
+	scope := spawn.NewScope(context.Background())
+	defer scope.Cancel()
+	if spawn.ScopeFromContext(scope.Context()) != scope {
+		t.Errorf("Expected scope from its context")
+	}
+}
+
+func TestScopeGo(t *testing.T) {
+	errFailed := errors.New("failed")
+	scope := spawn.NewScope(context.Background())
+
+	canceled := scope.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	scope.Go(func(ctx context.Context) error {
+		return errFailed
+	})
+	if err := scope.Wait(); err != errFailed {
+		t.Errorf("Expected first error %v, got %v", errFailed, err)
+	}
+	if err := canceled.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected other task to be canceled, got %v", err)
+	}
+}
+
+func TestScopeGoCollectAll(t *testing.T) {
+	errFailed := errors.New("failed")
+	scope := spawn.NewScope(context.Background(), spawn.WithCollectAll())
+	defer scope.Cancel()
+
+	var completed atomic.Int32
+	scope.Go(func(ctx context.Context) error {
+		return errFailed
+	})
+	scope.Go(func(ctx context.Context) error {
+		panic("boom")
+	}, spawn.WithRecover(func(any, []byte) {}))
+	scope.Go(func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() == nil {
+			completed.Add(1)
+		}
+		return nil
+	})
+	err := scope.Wait()
+	var pe *spawn.PanicError
+	if !errors.Is(err, errFailed) || !errors.As(err, &pe) {
+		t.Errorf("Expected both the error and the panic, got %v", err)
+	}
+	if completed.Load() != 1 {
+		t.Errorf("Expected errors not to cancel the scope")
+	}
+}
@@ -0,0 +1,105 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrStopped is returned when submitting a function to an Executor that has stopped.
+var ErrStopped = errors.New("spawn: executor stopped")
+
+// Executor runs submitted functions one at a time on a dedicated goroutine, in FIFO order.
+// It serializes access to state that is not safe for concurrent use, like an actor.
+// It is created by Serial, and stops when its context is canceled; pending functions are dropped.
+type Executor struct {
+	Handle
+	mu      sync.Mutex
+	queue   []func(context.Context)
+	signal  chan struct{}
+	stopped bool
+}
+
+// Serial starts a new Executor with the given context and options.
+func Serial(ctx context.Context, options ...Option) *Executor {
+	e := &Executor{signal: make(chan struct{}, 1)}
+	e.Handle = Run(ctx, e.loop, options...)
+	return e
+}
+
+func (e *Executor) loop(ctx context.Context) {
+	defer func() {
+		e.mu.Lock()
+		e.stopped = true
+		e.queue = nil
+		e.mu.Unlock()
+	}()
+	for {
+		select {
+		case <-e.signal:
+		case <-ctx.Done():
+			return
+		}
+		for ctx.Err() == nil {
+			f := e.pop()
+			if f == nil {
+				break
+			}
+			f(ctx)
+		}
+	}
+}
+
+func (e *Executor) pop() func(context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) == 0 {
+		return nil
+	}
+	f := e.queue[0]
+	e.queue[0] = nil
+	e.queue = e.queue[1:]
+	return f
+}
+
+// Do submits f to run after all previously submitted functions, without waiting for it.
+// It returns ErrStopped if the executor has stopped.
+func (e *Executor) Do(f func(context.Context)) error {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return ErrStopped
+	}
+	e.queue = append(e.queue, f)
+	e.mu.Unlock()
+	select {
+	case e.signal <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// DoWait is like Do, but waits for f to return. It returns the context error if the context is
+// canceled first, or ErrStopped if the executor stops before running f.
+func (e *Executor) DoWait(ctx context.Context, f func(context.Context)) error {
+	done := make(chan struct{})
+	if err := e.Do(func(ctx context.Context) {
+		defer close(done)
+		f(ctx)
+	}); err != nil {
+		return err
+	}
+	select {
+	case <-done:
+		return nil
+	case <-e.Done():
+		select {
+		case <-done:
+			return nil
+		default:
+			return ErrStopped
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,45 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestSerial(t *testing.T) {
+	ctx := context.Background()
+	exec := spawn.Serial(ctx)
+
+	var got []int // only accessed by the executor
+	for i := range 100 {
+		if err := exec.Do(func(ctx context.Context) {
+			got = append(got, i)
+		}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	var n int
+	if err := exec.DoWait(ctx, func(ctx context.Context) {
+		n = len(got)
+		if !slices.IsSorted(got) {
+			t.Errorf("Expected functions to run in FIFO order, got %v", got)
+		}
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n != 100 {
+		t.Errorf("Expected 100 functions to run before DoWait, got %d", n)
+	}
+
+	exec.Cancel()
+	exec.Join(ctx)
+	if err := exec.Do(func(ctx context.Context) {}); !errors.Is(err, spawn.ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+	if err := exec.DoWait(ctx, func(ctx context.Context) {}); !errors.Is(err, spawn.ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
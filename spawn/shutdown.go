@@ -0,0 +1,66 @@
+package spawn
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Shutdowner coordinates the shutdown of tasks in ordered phases. Tasks are added with a phase
+// number, and Shutdown cancels and joins the tasks of each phase, in increasing phase order,
+// before moving to the next phase. For example, tasks that accept new work can be stopped in
+// phase 0 before the tasks that flush it in phase 1.
+//
+// The zero value is ready to use. A Shutdowner must not be copied after first use.
+type Shutdowner struct {
+	mu       sync.Mutex
+	phases   map[int][]Handle
+	shutdown bool
+}
+
+// Add adds the task handle h to the given phase. If Shutdown has already been called,
+// the task is canceled immediately.
+func (s *Shutdowner) Add(phase int, h Handle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		h.Cancel()
+		return
+	}
+	if s.phases == nil {
+		s.phases = make(map[int][]Handle)
+	}
+	s.phases[phase] = append(s.phases[phase], h)
+}
+
+// Shutdown cancels and joins the added tasks phase by phase, in increasing phase order.
+// The tasks within a phase are canceled together. It returns the context error if the context
+// is canceled before all tasks complete, in which case the remaining phases are canceled
+// without waiting for them.
+func (s *Shutdowner) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.shutdown = true
+	phases := s.phases
+	s.phases = nil
+	s.mu.Unlock()
+
+	for _, phase := range slices.Sorted(maps.Keys(phases)) {
+		handles := phases[phase]
+		for _, h := range handles {
+			h.Cancel()
+		}
+		for _, h := range handles {
+			h.Join(ctx)
+		}
+		if err := ctx.Err(); err != nil {
+			for _, handles := range phases {
+				for _, h := range handles {
+					h.Cancel()
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
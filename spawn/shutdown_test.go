@@ -0,0 +1,59 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestShutdowner(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var order []string
+	stopped := func(name string) func(context.Context) {
+		return func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+
+	var s spawn.Shutdowner
+	s.Add(1, spawn.Run(ctx, stopped("flusher")))
+	s.Add(0, spawn.Run(ctx, stopped("intake")))
+	s.Add(2, spawn.Run(ctx, stopped("storage")))
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(order) != 3 || order[0] != "intake" || order[1] != "flusher" || order[2] != "storage" {
+		t.Errorf("Expected shutdown order [intake flusher storage], got %v", order)
+	}
+
+	late := spawn.Run(ctx, func(ctx context.Context) { <-ctx.Done() })
+	s.Add(0, late)
+	late.Join(ctx)
+}
+
+func TestShutdownerTimeout(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	defer close(release)
+
+	var s spawn.Shutdowner
+	s.Add(0, spawn.Run(ctx, func(ctx context.Context) { <-release }))
+	next := spawn.Run(ctx, func(ctx context.Context) { <-ctx.Done() })
+	s.Add(1, next)
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	next.Join(ctx)
+}
@@ -0,0 +1,55 @@
+package spawn
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// SignalError is the cause of the cancellation of a context returned by NotifyContext
+// when one of the signals arrives.
+type SignalError struct {
+	Signal os.Signal
+}
+
+// Error returns the error message.
+func (e *SignalError) Error() string {
+	return "spawn: received signal " + e.Signal.String()
+}
+
+// NotifyContext is like signal.NotifyContext: it returns a copy of the parent context that is
+// canceled when one of the signals arrives, when the returned stop function is called, or when
+// the parent context is canceled, whichever happens first. If no signals are given, all incoming
+// signals are relayed. The signal is available as a *SignalError with context.Cause, so the
+// handles of a service can be tied to SIGINT and SIGTERM by starting them with this context.
+func NotifyContext(parent context.Context, signals ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case sig := <-ch:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// OnSignal starts a new concurrent task that calls f with each of the signals that arrives, until
+// the task is canceled. If no signals are given, all incoming signals are relayed. Signals that
+// arrive while f is running are coalesced into one call.
+func OnSignal(ctx context.Context, f func(context.Context, os.Signal), signals ...os.Signal) Handle {
+	if f == nil {
+		panic("nil function for OnSignal")
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+	h := Chan(ctx, ch, f)
+	go func() {
+		<-h.Done()
+		signal.Stop(ch)
+	}()
+	return h
+}
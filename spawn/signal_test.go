@@ -0,0 +1,50 @@
+//go:build unix
+
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestNotifyContext(t *testing.T) {
+	ctx, stop := spawn.NotifyContext(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Expected context to be canceled by the signal")
+	}
+	var se *spawn.SignalError
+	if !errors.As(context.Cause(ctx), &se) || se.Signal != syscall.SIGUSR1 {
+		t.Errorf("Expected SIGUSR1 as the cause, got %v", context.Cause(ctx))
+	}
+}
+
+func TestOnSignal(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan os.Signal, 1)
+
+	handle := spawn.OnSignal(ctx, func(ctx context.Context, sig os.Signal) {
+		received <- sig
+	}, syscall.SIGUSR2)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	select {
+	case sig := <-received:
+		if sig != syscall.SIGUSR2 {
+			t.Errorf("Expected SIGUSR2, got %v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected f to be called with the signal")
+	}
+	handle.Cancel()
+	handle.Join(ctx)
+}
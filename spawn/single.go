@@ -0,0 +1,78 @@
+package spawn
+
+import (
+	"context"
+	"sync"
+)
+
+// Single deduplicates concurrent calls by key: callers of Do with the same key share one in-flight
+// execution and its result. The zero value is ready to use.
+type Single[K comparable, T any] struct {
+	mu    sync.Mutex
+	calls map[K]*singleCall[T]
+}
+
+type singleCall[T any] struct {
+	future  *Future[T]
+	waiters int
+}
+
+// Do calls f for the key unless a call for the same key is already in flight, and waits for its result.
+// The call runs as a task with the given options, under a context that carries the values of the
+// context of the caller that started it, but not its cancellation. If the context of a caller is
+// canceled, Do returns the context error for that caller only; the shared call is canceled once all
+// of its callers have abandoned it. Calls made after the shared call has completed start a new one.
+func (s *Single[K, T]) Do(ctx context.Context, key K, f func(context.Context) (T, error), options ...Option) (T, error) {
+	s.mu.Lock()
+	c, ok := s.calls[key]
+	if !ok {
+		if s.calls == nil {
+			s.calls = make(map[K]*singleCall[T])
+		}
+		c = new(singleCall[T])
+		s.calls[key] = c
+		c.future = Async(context.WithoutCancel(ctx), func(ctx context.Context) (T, error) {
+			defer s.forget(key, c)
+			return f(ctx)
+		}, options...)
+	}
+	c.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-c.future.Done():
+		s.mu.Lock()
+		c.waiters--
+		s.mu.Unlock()
+		return c.future.value, c.future.Err()
+	case <-ctx.Done():
+		s.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			s.forgetLocked(key, c)
+			c.future.Cancel()
+		}
+		s.mu.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// InFlight returns the number of keys with a call in flight.
+func (s *Single[K, T]) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.calls)
+}
+
+func (s *Single[K, T]) forget(key K, c *singleCall[T]) {
+	s.mu.Lock()
+	s.forgetLocked(key, c)
+	s.mu.Unlock()
+}
+
+func (s *Single[K, T]) forgetLocked(key K, c *singleCall[T]) {
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+}
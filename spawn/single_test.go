@@ -0,0 +1,90 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestSingle(t *testing.T) {
+	ctx := context.Background()
+	var s spawn.Single[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = s.Do(ctx, "key", func(ctx context.Context) (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := s.InFlight(); n != 1 {
+		t.Errorf("Expected 1 call in flight, got %d", n)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected f to be called once, got %d", n)
+	}
+	for _, v := range results {
+		if v != 42 {
+			t.Errorf("Expected all results to be 42, got %v", results)
+			break
+		}
+	}
+	if n := s.InFlight(); n != 0 {
+		t.Errorf("Expected no call in flight, got %d", n)
+	}
+}
+
+func TestSingleAbandon(t *testing.T) {
+	ctx := context.Background()
+	var s spawn.Single[int, int]
+	canceled := make(chan struct{})
+
+	f := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(canceled)
+		return 0, ctx.Err()
+	}
+	timeout1, cancel1 := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel1()
+	timeout2, cancel2 := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel2()
+
+	done := make(chan error)
+	go func() {
+		_, err := s.Do(timeout2, 1, f)
+		done <- err
+	}()
+	if _, err := s.Do(timeout1, 1, f); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	select {
+	case <-canceled:
+		t.Errorf("Expected shared call not to be canceled while a caller waits")
+	default:
+	}
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected shared call to be canceled after all callers abandoned it")
+	}
+}
@@ -3,6 +3,13 @@ package spawn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -10,14 +17,121 @@ import (
 type Handle interface {
 	// Join waits for the task to complete or the context to be canceled.
 	Join(context.Context)
+	// Done returns a channel that is closed when the task completes.
+	Done() <-chan struct{}
 	// Cancel stops the execution of the task.
 	Cancel()
+	// Err returns the error returned by the task after it completes, or nil if the task
+	// has not completed or does not return errors.
+	Err() error
+	// Panicked reports whether the task completed because of a panic recovered by WithRecover.
+	Panicked() bool
+	// TimedOut reports whether the task completed after its timeout set by WithTimeout expired.
+	TimedOut() bool
+	// Stats returns the execution statistics of the task.
+	Stats() Stats
+	// State returns the current state of the task.
+	State() TaskState
+	// Running reports whether the task is running, that is, whether its state is TaskRunning
+	// or TaskCanceling.
+	Running() bool
+}
+
+// TaskState is the state of a task, as returned by Handle.State and listed by Tasks.
+type TaskState int
+
+const (
+	// TaskPending is the state of a task that has been started but whose goroutine has not run yet.
+	TaskPending TaskState = iota
+	// TaskRunning is the state of a task that is running.
+	TaskRunning
+	// TaskCanceling is the state of a task whose context is canceled but which has not returned yet.
+	TaskCanceling
+	// TaskCompleted is the state of a task that returned, successfully or with an error, before it
+	// was canceled. A periodic task that stops after MaxRuns or Until is also completed.
+	TaskCompleted
+	// TaskCanceled is the state of a task that returned after it was canceled or its timeout expired.
+	TaskCanceled
+	// TaskPanicked is the state of a task that completed because of a panic recovered by WithRecover.
+	TaskPanicked
+)
+
+// String returns the name of the state.
+func (s TaskState) String() string {
+	switch s {
+	case TaskPending:
+		return "pending"
+	case TaskRunning:
+		return "running"
+	case TaskCanceling:
+		return "canceling"
+	case TaskCompleted:
+		return "completed"
+	case TaskCanceled:
+		return "canceled"
+	case TaskPanicked:
+		return "panicked"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s TaskState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Stats are the execution statistics of a task.
+type Stats struct {
+	// Started is the time the task started.
+	Started time.Time
+	// Completed is the time the task completed, or the zero time if it is running.
+	Completed time.Time
+	// Runs is the number of executions of the task function. For periodic tasks like Tick,
+	// it is the number of executions of the periodic function; for other tasks, it is 1
+	// once the task has completed.
+	Runs int64
+	// LastDuration is the duration of the last execution.
+	LastDuration time.Duration
+	// LastError is the error of the last execution.
+	LastError error
+	// Drained is the number of values processed by a Chan task with WithCleanup after it was canceled.
+	Drained int64
+	// Dropped is the number of values discarded by a Chan task with WithCleanup because the cleanup
+	// reached the bound set by WithDrainTimeout or WithDrainLimit.
+	Dropped int64
+}
+
+// ErrTimeout is the cause of the cancellation of the context of a task whose timeout set by WithTimeout expired.
+var ErrTimeout = errors.New("spawn: task timed out")
+
+// PanicError is the error of a task that panicked, if the panic was recovered by WithRecover.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error returns the string representation of the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
 }
 
 // taskHandle implements the Handle interface and contains control information for a task.
 type taskHandle struct {
-	done   chan struct{}
-	cancel context.CancelFunc
+	exited   chan struct{}
+	cancel   context.CancelFunc
+	err      error
+	panicked bool
+	timedOut bool
+	canceled bool
+	running  atomic.Bool
+	stopped  atomic.Bool
+	ctx      context.Context
+
+	mu    sync.Mutex
+	stats Stats
 }
 
 // Join blocks until the task completes or the context is canceled.
@@ -25,11 +139,16 @@ func (h *taskHandle) Join(ctx context.Context) {
 	select {
 	case <-ctx.Done():
 		// Context canceled or timed out
-	case <-h.done:
+	case <-h.exited:
 		// Task completed
 	}
 }
 
+// Done returns a channel that is closed when the task completes.
+func (h *taskHandle) Done() <-chan struct{} {
+	return h.exited
+}
+
 // Cancel stops the execution of the task.
 func (h *taskHandle) Cancel() {
 	if h.cancel != nil {
@@ -37,6 +156,233 @@ func (h *taskHandle) Cancel() {
 	}
 }
 
+// Err returns the error returned by the task after it completes.
+func (h *taskHandle) Err() error {
+	select {
+	case <-h.exited:
+		return h.err
+	default:
+		return nil
+	}
+}
+
+// Panicked reports whether the task completed because of a recovered panic.
+func (h *taskHandle) Panicked() bool {
+	select {
+	case <-h.exited:
+		return h.panicked
+	default:
+		return false
+	}
+}
+
+// TimedOut reports whether the task completed after its timeout expired.
+func (h *taskHandle) TimedOut() bool {
+	select {
+	case <-h.exited:
+		return h.timedOut
+	default:
+		return false
+	}
+}
+
+// State returns the current state of the task.
+func (h *taskHandle) State() TaskState {
+	select {
+	case <-h.exited:
+		return h.state()
+	default:
+		if !h.running.Load() {
+			return TaskPending
+		}
+		if h.ctx.Err() != nil && !h.stopped.Load() {
+			return TaskCanceling
+		}
+		return TaskRunning
+	}
+}
+
+// state returns the final state of the completed task.
+func (h *taskHandle) state() TaskState {
+	switch {
+	case h.panicked:
+		return TaskPanicked
+	case h.canceled:
+		return TaskCanceled
+	default:
+		return TaskCompleted
+	}
+}
+
+// Running reports whether the task is running.
+func (h *taskHandle) Running() bool {
+	s := h.State()
+	return s == TaskRunning || s == TaskCanceling
+}
+
+// stop cancels the task when it completes by itself, so that it is not reported as canceled.
+func (h *taskHandle) stop() {
+	h.stopped.Store(true)
+	h.Cancel()
+}
+
+// Stats returns the execution statistics of the task.
+func (h *taskHandle) Stats() Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stats
+}
+
+// record records an execution that started at t and failed with err, and returns the number of executions.
+func (h *taskHandle) record(t time.Time, err error) int64 {
+	d := time.Since(t)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.Runs++
+	h.stats.LastDuration = d
+	h.stats.LastError = err
+	return h.stats.Runs
+}
+
+// start starts the task t that runs f and returns its handle.
+func start(ctx context.Context, t *task, f func(context.Context) error) *taskHandle {
+	var cancel context.CancelFunc
+	if t.timeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(ctx, t.timeout, ErrTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	h := &taskHandle{
+		exited: make(chan struct{}),
+		cancel: cancel,
+		ctx:    ctx,
+		stats:  Stats{Started: time.Now()},
+	}
+	t.handle = h
+	var id uint64
+	if t.name != "" {
+		id = registry.add(t.name, h)
+	}
+	recorder := GetMetricsRecorder()
+	recorder.Started(t.name)
+	t.spawn(func() {
+		defer close(h.exited)
+		defer cancel()
+		if id != 0 {
+			defer registry.remove(id)
+		}
+		h.running.Store(true)
+		started := time.Now()
+		callCtx, end := ctx, untraced
+		if t.tracer != nil {
+			callCtx, end = t.tracer.Start(ctx, t.name)
+		}
+		h.err = t.call(callCtx, h, f)
+		h.canceled = ctx.Err() != nil && !h.stopped.Load()
+		end(h.state(), h.err)
+		h.timedOut = t.timeout > 0 && context.Cause(ctx) == ErrTimeout
+		if !t.periodic {
+			h.record(started, h.err)
+		}
+		h.mu.Lock()
+		h.stats.Completed = time.Now()
+		h.mu.Unlock()
+		if pe, ok := h.err.(*PanicError); ok && h.panicked {
+			recorder.Panicked(t.name, pe.Value)
+		}
+		recorder.Stopped(t.name, time.Since(started), h.err)
+		if t.collector != nil {
+			t.collector.report(ctx, t.name, h.err)
+		}
+		if t.exited != nil {
+			t.exited(h.err)
+		}
+	})
+	return h
+}
+
+// counted returns a function that calls f unless the task is canceled, and records each execution
+// in the stats of the task, for tasks that execute a function periodically.
+func (t *task) counted(f func(context.Context)) func(context.Context) {
+	t.periodic = true
+	return func(ctx context.Context) {
+		if ctx.Err() != nil {
+			return
+		}
+		began := time.Now()
+		t.protect(ctx, func() { f(ctx) })
+		t.executed(ctx, began)
+	}
+}
+
+// protect calls f. If WithRestartOnPanic is set, it recovers from a panic in f, logs it,
+// and waits for the backoff before returning.
+func (o *taskOptions) protect(ctx context.Context, f func()) {
+	if o.restart <= 0 {
+		f()
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			slog.Error("spawn: recovered panic, restarting", "task", o.name, "panic", r, "stack", string(stack))
+			recorder := GetMetricsRecorder()
+			recorder.Panicked(o.name, r)
+			timer := time.NewTimer(o.restart)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				recorder.Restarted(o.name)
+			case <-ctx.Done():
+			}
+		}
+	}()
+	f()
+}
+
+// guarded returns f protected by WithRestartOnPanic, if set.
+func guarded[T any](t *task, f func(context.Context, T)) func(context.Context, T) {
+	if t.restart <= 0 {
+		return f
+	}
+	return func(ctx context.Context, v T) {
+		t.protect(ctx, func() { f(ctx, v) })
+	}
+}
+
+// executed records an execution of the periodic function that began at the given time, and cancels
+// the task if it has reached the number of runs set by MaxRuns or the condition set by Until.
+func (t *task) executed(ctx context.Context, began time.Time) {
+	runs := t.handle.record(began, nil)
+	if (t.maxRuns > 0 && runs >= t.maxRuns) || (t.until != nil && t.until(ctx)) {
+		t.handle.stop()
+	}
+}
+
+// spawn runs f on a new goroutine, or on the pool set by WithPool.
+func (o *taskOptions) spawn(f func()) {
+	if o.pool != nil {
+		o.pool.Go(f)
+	} else {
+		go f()
+	}
+}
+
+// call calls f, recovering from a panic if WithRecover is set.
+func (o *taskOptions) call(ctx context.Context, h *taskHandle, f func(context.Context) error) (err error) {
+	if o.recover != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				h.panicked = true
+				err = &PanicError{Value: r, Stack: stack}
+				o.recover(r, stack)
+			}
+		}()
+	}
+	return f(ctx)
+}
+
 // Run starts a new concurrent task with the given context and function.
 //
 // Parameters:
@@ -45,18 +391,17 @@ func (h *taskHandle) Cancel() {
 //
 // Returns:
 //   - Handle: A handle that can be used to control the task.
-func Run(ctx context.Context, f func(context.Context)) Handle {
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-	go func() {
-		defer close(h.done)
-		defer cancel()
+func Run(ctx context.Context, f func(context.Context), options ...Option) Handle {
+	return start(ctx, newTask("Run", kindTask, options), func(ctx context.Context) error {
 		f(ctx)
-	}()
-	return h
+		return nil
+	})
+}
+
+// RunErr starts a new concurrent task with the given context and a function that returns an error.
+// The error is available from the Err method of the returned handle after the task completes.
+func RunErr(ctx context.Context, f func(context.Context) error, options ...Option) Handle {
+	return start(ctx, newTask("RunErr", kindTask, options), f)
 }
 
 // Tick starts a task that executes a function at specified intervals.
@@ -68,16 +413,16 @@ func Run(ctx context.Context, f func(context.Context)) Handle {
 //
 // Returns:
 //   - Handle: A handle that can be used to control the task.
-func Tick(ctx context.Context, f func(context.Context), d time.Duration) Handle {
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+func Tick(ctx context.Context, f func(context.Context), d time.Duration, options ...TickOption) Handle {
+	t := newTask("Tick", kindTask|kindTick|kindPeriodic|kindRestart, options)
+	f = t.counted(f)
+	return start(ctx, t, func(ctx context.Context) error {
+		if t.immediate {
+			f(ctx)
+		}
+		if t.jitter > 0 {
+			return tickJitter(ctx, f, d, t.jitter)
+		}
 		ticker := time.NewTicker(d)
 		defer ticker.Stop()
 
@@ -86,21 +431,316 @@ func Tick(ctx context.Context, f func(context.Context), d time.Duration) Handle
 			case <-ticker.C:
 				f(ctx)
 			case <-ctx.Done():
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
+}
+
+// tickJitter calls f periodically like Tick, but randomizes each interval within ±jitter of d.
+func tickJitter(ctx context.Context, f func(context.Context), d time.Duration, jitter float64) error {
+	next := func() time.Duration {
+		return d + time.Duration(float64(d)*jitter*(2*rand.Float64()-1))
+	}
+	timer := time.NewTimer(next())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			timer.Reset(next())
+			f(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// TickFunc starts a new concurrent task that calls f right away and then again after each delay
+// returned by f, until the context is canceled or f returns a non-positive delay.
+// It allows adaptive polling, e.g. backing off when idle and speeding up when busy.
+func TickFunc(ctx context.Context, f func(context.Context) time.Duration, options ...TickOption) Handle {
+	t := newTask("TickFunc", kindTask|kindPeriodic|kindRestart, options)
+	t.periodic = true
+	// last is the last delay, reused if f panics and WithRestartOnPanic is set.
+	var last time.Duration
+	next := func(ctx context.Context) time.Duration {
+		if ctx.Err() != nil {
+			return 0
+		}
+		began := time.Now()
+		t.protect(ctx, func() { last = f(ctx) })
+		t.executed(ctx, began)
+		return last
+	}
+	return start(ctx, t, func(ctx context.Context) error {
+		d := next(ctx)
+		if d <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				if d = next(ctx); d <= 0 {
+					return nil
+				}
+				timer.Reset(d)
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// Every starts a new concurrent task that calls f at the wall-clock times that are offset past
+// a multiple of period, until the context is canceled. For example, a period of time.Hour and an
+// offset of 5*time.Minute call f at 00:05, 01:05, 02:05, and so on. Multiples of period are
+// counted from the zero time in UTC, so a period of 24 hours is aligned to midnight UTC, unless
+// InLocation is given: then they are counted in the wall clock of that location, so a period of
+// 24 hours is aligned to local midnight and follows daylight saving time transitions.
+// Each run is scheduled from the wall clock, so the schedule does not drift.
+func Every(ctx context.Context, period, offset time.Duration, f func(context.Context), options ...Option) Handle {
+	if period <= 0 {
+		panic("non-positive period for Every")
+	}
+	t := newTask("Every", kindTask|kindPeriodic|kindRestart|kindLocation, options)
+	f = t.counted(f)
+	loc := t.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return start(ctx, t, func(ctx context.Context) error {
+		for {
+			now := time.Now().In(loc)
+			// Align in the wall clock of the location, represented as a UTC time.
+			y, m, d := now.Date()
+			wall := time.Date(y, m, d, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.UTC)
+			wall = wall.Truncate(period).Add(offset % period)
+			next := inLocation(wall, loc)
+			for !next.After(now) {
+				wall = wall.Add(period)
+				next = inLocation(wall, loc)
+			}
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C:
+				f(ctx)
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			}
+		}
+	})
+}
+
+// After starts a new concurrent task that calls f once after the duration d, unless the context
+// is canceled first. The handle completes when f returns or the task is canceled.
+func After(ctx context.Context, d time.Duration, f func(context.Context), options ...Option) Handle {
+	t := newTask("After", kindTask|kindPeriodic|kindRestart, options)
+	f = t.counted(f)
+	return start(ctx, t, func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			f(ctx)
+		case <-ctx.Done():
+		}
+		return nil
+	})
+}
+
+// task is a task being started, with its options and the state shared by its functions.
+type task struct {
+	taskOptions
+
+	handle   *taskHandle
+	periodic bool
+	exited   func(err error)
+
+	// State of the cleanup of a Chan task.
+	drainDeadline time.Time
+	drained       int64
 }
 
-type chanOptions struct {
+// newTask returns a task for the function fn with the given options, which must be of the accepted kinds.
+func newTask(fn string, accepted optionKind, options []Option) *task {
+	t := new(task)
+	t.apply(fn, accepted, options)
+	return t
+}
+
+type taskOptions struct {
+	name      string
+	recover   func(any, []byte)
+	timeout   time.Duration
+	restart   time.Duration
+	pool      *GoPool
+	collector *Collector
+	tracer    Tracer
+
+	// accepted are the kinds of options accepted by the function being configured, and rejected
+	// is the name of the first option given to it that is not of those kinds.
+	accepted optionKind
+	rejected string
+
+	// Options for the Chan functions.
 	tickerInterval time.Duration
 	tickerFunction func(context.Context)
 	cleanup        bool
+	priority       bool
+	exitOnClosed   bool
+	sampleInterval time.Duration
+	onSample       func(ctx context.Context, index, length, capacity int)
+	onClosed       func(context.Context, int)
+	drainTimeout   time.Duration
+	drainLimit     int64
+
+	// Options for ChanBatch.
+	maxBatch int
+	maxDelay time.Duration
+
+	// Options for Throttle.
+	trailing bool
+
+	// Options for Actor.
+	mailbox int
+
+	// Options for Map.
+	collectAll bool
+
+	// Options for RunRetry.
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	// Options for Tick.
+	immediate bool
+	jitter    float64
+	maxRuns   int64
+	until     func(context.Context) bool
+
+	// Options for Cron and Every.
+	location *time.Location
+}
+
+// Option is a configuration option for tasks. The functions that start tasks panic if given an
+// option that does not apply to them, such as MaxRuns given to Run, rather than ignore it.
+type Option func(*taskOptions)
+
+// ChanOption is a configuration option for the Chan functions. It is an Option, documented as
+// applying to the Chan functions and the functions built on them.
+type ChanOption = Option
+
+// TickOption is a configuration option for Tick. It is an Option, documented as applying to Tick;
+// some also apply to the other periodic tasks.
+type TickOption = Option
+
+// optionKind is a set of kinds of options, used to reject the options that do not apply to a function.
+type optionKind uint
+
+const (
+	kindTask       optionKind = 1 << iota // options of all tasks, such as WithTimeout
+	kindTick                              // WithImmediate and WithJitter
+	kindPeriodic                          // MaxRuns and Until
+	kindRestart                           // WithRestartOnPanic
+	kindChan                              // options of the Chan functions
+	kindBatch                             // WithMaxBatch and WithMaxDelay
+	kindMailbox                           // WithMailbox
+	kindLocation                          // InLocation
+	kindRetry                             // MaxAttempts and Backoff
+	kindTrailing                          // WithTrailing
+	kindCollectAll                        // WithCollectAll
+)
+
+// option returns an Option of the given kind and name that configures the options with f.
+func option(kind optionKind, name string, f func(*taskOptions)) Option {
+	return func(o *taskOptions) {
+		if o.accepted&kind == 0 && o.rejected == "" {
+			o.rejected = name
+		}
+		f(o)
+	}
+}
+
+// WithImmediate makes Tick call the function once right away, before waiting for the first interval.
+func WithImmediate() TickOption {
+	return option(kindTick, "WithImmediate", func(o *taskOptions) {
+		o.immediate = true
+	})
+}
+
+// WithJitter randomizes each interval of Tick within ±fraction of the interval, in the range [0, 1).
+// For example, a jitter of 0.1 turns an interval of 10s into a random interval between 9s and 11s.
+// It spreads out the ticks of many tasks started on the same schedule.
+func WithJitter(fraction float64) TickOption {
+	if fraction < 0 || fraction >= 1 {
+		panic("jitter out of range [0, 1) for WithJitter")
+	}
+	return option(kindTick, "WithJitter", func(o *taskOptions) {
+		o.jitter = fraction
+	})
+}
+
+// WithTimeout sets a timeout for the task: its context is canceled with ErrTimeout as the cause
+// after the duration d. The handle reports TimedOut if the task completes after the timeout expired.
+func WithTimeout(d time.Duration) Option {
+	if d <= 0 {
+		panic("non-positive timeout for WithTimeout")
+	}
+	return option(kindTask, "WithTimeout", func(o *taskOptions) {
+		o.timeout = d
+	})
 }
 
-// ChanOption is a configuration option for the Chan functions.
-type ChanOption func(*chanOptions)
+// MaxRuns makes a periodic task, like Tick, complete after n executions of its function.
+func MaxRuns(n int) TickOption {
+	if n <= 0 {
+		panic("non-positive runs for MaxRuns")
+	}
+	return option(kindPeriodic, "MaxRuns", func(o *taskOptions) {
+		o.maxRuns = int64(n)
+	})
+}
+
+// Until makes a periodic task, like Tick, complete once the condition returns true.
+// The condition is checked after each execution of the periodic function.
+func Until(cond func(context.Context) bool) TickOption {
+	if cond == nil {
+		panic("nil condition for Until")
+	}
+	return option(kindPeriodic, "Until", func(o *taskOptions) {
+		o.until = cond
+	})
+}
+
+// WithRestartOnPanic makes a periodic task, like Tick, or a task of the ChanN functions or Actor survive panics in its functions:
+// a panic is recovered and logged with slog, and the task resumes after the backoff. Unlike WithRecover,
+// which completes the task, the task keeps running. Panics and restarts are recorded by the global
+// MetricsRecorder.
+func WithRestartOnPanic(backoff time.Duration) Option {
+	if backoff <= 0 {
+		panic("non-positive backoff for WithRestartOnPanic")
+	}
+	return option(kindRestart, "WithRestartOnPanic", func(o *taskOptions) {
+		o.restart = backoff
+	})
+}
+
+// WithRecover recovers from a panic in the task and calls the handler with the panic value and the stack trace.
+// The task completes, its handle reports Panicked, and its Err method returns a *PanicError.
+func WithRecover(handler func(v any, stack []byte)) Option {
+	if handler == nil {
+		panic("nil handler for WithRecover")
+	}
+	return option(kindTask, "WithRecover", func(o *taskOptions) {
+		o.recover = handler
+	})
+}
 
 // WithTicker sets the interval and function for a ticker.
 func WithTicker(interval time.Duration, f func(context.Context)) ChanOption {
@@ -110,299 +750,618 @@ func WithTicker(interval time.Duration, f func(context.Context)) ChanOption {
 	if f == nil {
 		panic("nil function for WithTicker")
 	}
-	return func(o *chanOptions) {
+	return option(kindChan, "WithTicker", func(o *taskOptions) {
 		o.tickerInterval = interval
 		o.tickerFunction = f
-	}
+	})
 }
 
-// WithCleanup specifies whether to clean up the channel after the context is canceled.
+// WithCleanup specifies whether to clean up the channel after the context is canceled: values
+// already available in the channel are processed before the task completes. The cleanup is
+// unbounded unless WithDrainTimeout or WithDrainLimit is given.
 func WithCleanup(cleanup bool) ChanOption {
-	return func(o *chanOptions) {
+	return option(kindChan, "WithCleanup", func(o *taskOptions) {
 		o.cleanup = cleanup
+	})
+}
+
+// WithDrainTimeout bounds the cleanup of WithCleanup to the duration d: once it has elapsed, the
+// values left in the channels are discarded and counted in Stats.Dropped. The timeout is checked
+// between values, so a slow call to the handler is not interrupted.
+func WithDrainTimeout(d time.Duration) ChanOption {
+	if d <= 0 {
+		panic("non-positive timeout for WithDrainTimeout")
+	}
+	return option(kindChan, "WithDrainTimeout", func(o *taskOptions) {
+		o.drainTimeout = d
+	})
+}
+
+// WithDrainLimit bounds the cleanup of WithCleanup to n values across all channels: once n values
+// have been processed, the values left in the channels are discarded and counted in Stats.Dropped.
+func WithDrainLimit(n int) ChanOption {
+	if n <= 0 {
+		panic("non-positive limit for WithDrainLimit")
+	}
+	return option(kindChan, "WithDrainLimit", func(o *taskOptions) {
+		o.drainLimit = int64(n)
+	})
+}
+
+// WithPriority makes the ChanN functions process ready values from lower-indexed channels first.
+// By default, one of the ready channels is chosen at random. For example, with Chan2(ctx, control, f1,
+// data, f2, WithPriority()), pending control messages are processed before any data.
+func WithPriority() ChanOption {
+	return option(kindChan, "WithPriority", func(o *taskOptions) {
+		o.priority = true
+	})
+}
+
+// WithExitOnClosed makes the Chan functions exit the task as soon as any of the channels is closed.
+// By default, a closed channel is ignored, and the task exits when all of the channels are closed.
+func WithExitOnClosed() ChanOption {
+	return option(kindChan, "WithExitOnClosed", func(o *taskOptions) {
+		o.exitOnClosed = true
+	})
+}
+
+// WithOnClosed sets the function called by the Chan functions when one of the channels is closed,
+// with the 0-based index of the channel (0 for ch1, 1 for ch2, and so on).
+func WithOnClosed(f func(ctx context.Context, index int)) ChanOption {
+	if f == nil {
+		panic("nil function for WithOnClosed")
+	}
+	return option(kindChan, "WithOnClosed", func(o *taskOptions) {
+		o.onClosed = f
+	})
+}
+
+// WithSaturation samples the length and capacity of the channels of the Chan functions at the given
+// interval, and reports each sample to f, if not nil, and to the global MetricsRecorder if it implements
+// SaturationRecorder. The index is 0 for ch1, 1 for ch2, and so on. A channel whose length stays close to
+// its capacity has a consumer that is falling behind.
+func WithSaturation(interval time.Duration, f func(ctx context.Context, index, length, capacity int)) ChanOption {
+	if interval <= 0 {
+		panic("non-positive interval for WithSaturation")
+	}
+	return option(kindChan, "WithSaturation", func(o *taskOptions) {
+		o.sampleInterval = interval
+		o.onSample = f
+	})
+}
+
+// gauge returns a function that returns the length and capacity of ch.
+func gauge[T any](ch <-chan T) func() (int, int) {
+	return func() (int, int) {
+		return len(ch), cap(ch)
 	}
 }
 
-func (o *chanOptions) apply(opts []ChanOption) {
+// sample starts sampling the saturation of the channels if WithSaturation is set,
+// and returns a function that stops sampling.
+func (o *taskOptions) sample(ctx context.Context, gauges ...func() (int, int)) (stop func()) {
+	if o.sampleInterval <= 0 {
+		return func() {}
+	}
+	recorder, _ := GetMetricsRecorder().(SaturationRecorder)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(o.sampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for i, g := range gauges {
+					length, capacity := g()
+					if recorder != nil {
+						recorder.Saturation(o.name, i, length, capacity)
+					}
+					if o.onSample != nil {
+						o.onSample(ctx, i, length, capacity)
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// closed handles the closing of the channel at index, given the number of open channels,
+// and reports whether the task should exit.
+func (o *taskOptions) closed(ctx context.Context, index int, open *int) bool {
+	if o.onClosed != nil {
+		o.onClosed(ctx, index)
+	}
+	*open--
+	return o.exitOnClosed || *open == 0
+}
+
+// apply applies the options given to the function fn, and panics if one of them is not of the accepted kinds.
+func (o *taskOptions) apply(fn string, accepted optionKind, opts []Option) {
+	o.accepted = accepted
 	for _, opt := range opts {
 		opt(o)
 	}
+	if o.rejected != "" {
+		panic(fmt.Sprintf("unsupported option %s for %s", o.rejected, fn))
+	}
+}
+
+// poll processes a value from ch with f if one is ready, and reports whether a value was processed.
+func poll[T any](ctx context.Context, ch <-chan T, f func(context.Context, T)) bool {
+	select {
+	case v, ok := <-ch:
+		if ok {
+			f(ctx, v)
+		}
+		return ok
+	default:
+		return false
+	}
 }
 
-func cleanup[T any](ctx context.Context, ch <-chan T, f func(context.Context, T)) {
+// cleanup processes the values remaining in ch with f after the task is canceled, within the bounds
+// set by WithDrainTimeout and WithDrainLimit, which are shared by all channels of the task. Once a
+// bound is reached, the values buffered in ch are discarded.
+func cleanup[T any](t *task, ctx context.Context, ch <-chan T, f func(context.Context, T)) {
+	if t.drainTimeout > 0 && t.drainDeadline.IsZero() {
+		t.drainDeadline = time.Now().Add(t.drainTimeout)
+	}
 	for {
+		if (t.drainLimit > 0 && t.drained >= t.drainLimit) ||
+			(t.drainTimeout > 0 && !time.Now().Before(t.drainDeadline)) {
+			t.drop(discard(ch))
+			return
+		}
 		select {
-		case v := <-ch:
+		case v, ok := <-ch:
+			if !ok {
+				return
+			}
 			f(ctx, v)
+			t.drained++
+			t.handle.mu.Lock()
+			t.handle.stats.Drained++
+			t.handle.mu.Unlock()
 		default:
 			return
 		}
 	}
 }
 
+// discard receives and discards the values buffered in ch, and returns their number.
+func discard[T any](ch <-chan T) int64 {
+	var n int64
+	for range cap(ch) {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return n
+			}
+			n++
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// drop records n values dropped by cleanup.
+func (t *task) drop(n int64) {
+	t.handle.mu.Lock()
+	t.handle.stats.Dropped += n
+	t.handle.mu.Unlock()
+}
+
 // Chan starts a task that processes values from a channel.
 func Chan[T any](ctx context.Context, ch <-chan T, f func(context.Context, T), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
+	return startChan(ctx, newTask("Chan", kindTask|kindChan|kindRestart, options), ch, f)
+}
 
-	go func() {
-		defer close(h.done)
-		defer cancel()
+// startChan starts the task t that processes values from ch, like Chan.
+func startChan[T any](ctx context.Context, t *task, ch <-chan T, f func(context.Context, T)) Handle {
+	f = guarded(t, f)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch))()
+		open := 1
 		for {
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch:
+				if !ok {
+					ch = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch, f)
+				if t.cleanup {
+					cleanup(t, ctx, ch, f)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
 
 // Chan2 starts a task that processes values from channel 1 or channel 2.
 func Chan2[T1 any, T2 any](ctx context.Context, ch1 <-chan T1, f1 func(context.Context, T1), ch2 <-chan T2, f2 func(context.Context, T2), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+	t := newTask("Chan2", kindTask|kindChan|kindRestart, options)
+	f1 = guarded(t, f1)
+	f2 = guarded(t, f2)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch1), gauge(ch2))()
+		open := 2
 		for {
+			if t.priority && ctx.Err() == nil && poll(ctx, ch1, f1) {
+				continue
+			}
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch1:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch1:
+				if !ok {
+					ch1 = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f1(ctx, v)
-			case v := <-ch2:
+			case v, ok := <-ch2:
+				if !ok {
+					ch2 = nil
+					if t.closed(ctx, 1, &open) {
+						return nil
+					}
+					continue
+				}
 				f2(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch1, f1)
-					cleanup(ctx, ch2, f2)
+				if t.cleanup {
+					cleanup(t, ctx, ch1, f1)
+					cleanup(t, ctx, ch2, f2)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
 
 // Chan3 starts a task that processes values from channel 1, channel 2, or channel 3.
 func Chan3[T1 any, T2 any, T3 any](ctx context.Context, ch1 <-chan T1, f1 func(context.Context, T1), ch2 <-chan T2, f2 func(context.Context, T2), ch3 <-chan T3, f3 func(context.Context, T3), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+	t := newTask("Chan3", kindTask|kindChan|kindRestart, options)
+	f1 = guarded(t, f1)
+	f2 = guarded(t, f2)
+	f3 = guarded(t, f3)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch1), gauge(ch2), gauge(ch3))()
+		open := 3
 		for {
+			if t.priority && ctx.Err() == nil && (poll(ctx, ch1, f1) || poll(ctx, ch2, f2)) {
+				continue
+			}
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch1:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch1:
+				if !ok {
+					ch1 = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f1(ctx, v)
-			case v := <-ch2:
+			case v, ok := <-ch2:
+				if !ok {
+					ch2 = nil
+					if t.closed(ctx, 1, &open) {
+						return nil
+					}
+					continue
+				}
 				f2(ctx, v)
-			case v := <-ch3:
+			case v, ok := <-ch3:
+				if !ok {
+					ch3 = nil
+					if t.closed(ctx, 2, &open) {
+						return nil
+					}
+					continue
+				}
 				f3(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch1, f1)
-					cleanup(ctx, ch2, f2)
-					cleanup(ctx, ch3, f3)
+				if t.cleanup {
+					cleanup(t, ctx, ch1, f1)
+					cleanup(t, ctx, ch2, f2)
+					cleanup(t, ctx, ch3, f3)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
 
 // Chan4 starts a task that processes values from channel 1, channel 2, channel 3, or channel 4.
 func Chan4[T1 any, T2 any, T3 any, T4 any](ctx context.Context, ch1 <-chan T1, f1 func(context.Context, T1), ch2 <-chan T2, f2 func(context.Context, T2), ch3 <-chan T3, f3 func(context.Context, T3), ch4 <-chan T4, f4 func(context.Context, T4), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+	t := newTask("Chan4", kindTask|kindChan|kindRestart, options)
+	f1 = guarded(t, f1)
+	f2 = guarded(t, f2)
+	f3 = guarded(t, f3)
+	f4 = guarded(t, f4)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch1), gauge(ch2), gauge(ch3), gauge(ch4))()
+		open := 4
 		for {
+			if t.priority && ctx.Err() == nil && (poll(ctx, ch1, f1) || poll(ctx, ch2, f2) || poll(ctx, ch3, f3)) {
+				continue
+			}
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch1:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch1:
+				if !ok {
+					ch1 = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f1(ctx, v)
-			case v := <-ch2:
+			case v, ok := <-ch2:
+				if !ok {
+					ch2 = nil
+					if t.closed(ctx, 1, &open) {
+						return nil
+					}
+					continue
+				}
 				f2(ctx, v)
-			case v := <-ch3:
+			case v, ok := <-ch3:
+				if !ok {
+					ch3 = nil
+					if t.closed(ctx, 2, &open) {
+						return nil
+					}
+					continue
+				}
 				f3(ctx, v)
-			case v := <-ch4:
+			case v, ok := <-ch4:
+				if !ok {
+					ch4 = nil
+					if t.closed(ctx, 3, &open) {
+						return nil
+					}
+					continue
+				}
 				f4(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch1, f1)
-					cleanup(ctx, ch2, f2)
-					cleanup(ctx, ch3, f3)
-					cleanup(ctx, ch4, f4)
+				if t.cleanup {
+					cleanup(t, ctx, ch1, f1)
+					cleanup(t, ctx, ch2, f2)
+					cleanup(t, ctx, ch3, f3)
+					cleanup(t, ctx, ch4, f4)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
 
 // Chan5 starts a task that processes values from channel 1, channel 2, channel 3, channel 4, or channel 5.
 func Chan5[T1 any, T2 any, T3 any, T4 any, T5 any](ctx context.Context, ch1 <-chan T1, f1 func(context.Context, T1), ch2 <-chan T2, f2 func(context.Context, T2), ch3 <-chan T3, f3 func(context.Context, T3), ch4 <-chan T4, f4 func(context.Context, T4), ch5 <-chan T5, f5 func(context.Context, T5), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+	t := newTask("Chan5", kindTask|kindChan|kindRestart, options)
+	f1 = guarded(t, f1)
+	f2 = guarded(t, f2)
+	f3 = guarded(t, f3)
+	f4 = guarded(t, f4)
+	f5 = guarded(t, f5)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch1), gauge(ch2), gauge(ch3), gauge(ch4), gauge(ch5))()
+		open := 5
 		for {
+			if t.priority && ctx.Err() == nil && (poll(ctx, ch1, f1) || poll(ctx, ch2, f2) || poll(ctx, ch3, f3) || poll(ctx, ch4, f4)) {
+				continue
+			}
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch1:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch1:
+				if !ok {
+					ch1 = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f1(ctx, v)
-			case v := <-ch2:
+			case v, ok := <-ch2:
+				if !ok {
+					ch2 = nil
+					if t.closed(ctx, 1, &open) {
+						return nil
+					}
+					continue
+				}
 				f2(ctx, v)
-			case v := <-ch3:
+			case v, ok := <-ch3:
+				if !ok {
+					ch3 = nil
+					if t.closed(ctx, 2, &open) {
+						return nil
+					}
+					continue
+				}
 				f3(ctx, v)
-			case v := <-ch4:
+			case v, ok := <-ch4:
+				if !ok {
+					ch4 = nil
+					if t.closed(ctx, 3, &open) {
+						return nil
+					}
+					continue
+				}
 				f4(ctx, v)
-			case v := <-ch5:
+			case v, ok := <-ch5:
+				if !ok {
+					ch5 = nil
+					if t.closed(ctx, 4, &open) {
+						return nil
+					}
+					continue
+				}
 				f5(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch1, f1)
-					cleanup(ctx, ch2, f2)
-					cleanup(ctx, ch3, f3)
-					cleanup(ctx, ch4, f4)
-					cleanup(ctx, ch5, f5)
+				if t.cleanup {
+					cleanup(t, ctx, ch1, f1)
+					cleanup(t, ctx, ch2, f2)
+					cleanup(t, ctx, ch3, f3)
+					cleanup(t, ctx, ch4, f4)
+					cleanup(t, ctx, ch5, f5)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
 
 // Chan6 starts a task that processes values from channel 1, channel 2, channel 3, channel 4, channel 5, or channel 6.
 func Chan6[T1 any, T2 any, T3 any, T4 any, T5 any, T6 any](ctx context.Context, ch1 <-chan T1, f1 func(context.Context, T1), ch2 <-chan T2, f2 func(context.Context, T2), ch3 <-chan T3, f3 func(context.Context, T3), ch4 <-chan T4, f4 func(context.Context, T4), ch5 <-chan T5, f5 func(context.Context, T5), ch6 <-chan T6, f6 func(context.Context, T6), options ...ChanOption) Handle {
-	var o chanOptions
-	o.apply(options)
-	ctx, cancel := context.WithCancel(ctx)
-	h := &taskHandle{
-		done:   make(chan struct{}),
-		cancel: cancel,
-	}
-
-	go func() {
-		defer close(h.done)
-		defer cancel()
+	t := newTask("Chan6", kindTask|kindChan|kindRestart, options)
+	f1 = guarded(t, f1)
+	f2 = guarded(t, f2)
+	f3 = guarded(t, f3)
+	f4 = guarded(t, f4)
+	f5 = guarded(t, f5)
+	f6 = guarded(t, f6)
+	return start(ctx, t, func(ctx context.Context) error {
 		var tc <-chan time.Time
-		if o.tickerInterval > 0 {
-			ticker := time.NewTicker(o.tickerInterval)
+		if t.tickerInterval > 0 {
+			ticker := time.NewTicker(t.tickerInterval)
 			defer ticker.Stop()
 			tc = ticker.C
 		}
 
+		defer t.sample(ctx, gauge(ch1), gauge(ch2), gauge(ch3), gauge(ch4), gauge(ch5), gauge(ch6))()
+		open := 6
 		for {
+			if t.priority && ctx.Err() == nil && (poll(ctx, ch1, f1) || poll(ctx, ch2, f2) || poll(ctx, ch3, f3) || poll(ctx, ch4, f4) || poll(ctx, ch5, f5)) {
+				continue
+			}
 			select {
 			case <-tc:
-				o.tickerFunction(ctx)
-			case v := <-ch1:
+				t.tickerFunction(ctx)
+			case v, ok := <-ch1:
+				if !ok {
+					ch1 = nil
+					if t.closed(ctx, 0, &open) {
+						return nil
+					}
+					continue
+				}
 				f1(ctx, v)
-			case v := <-ch2:
+			case v, ok := <-ch2:
+				if !ok {
+					ch2 = nil
+					if t.closed(ctx, 1, &open) {
+						return nil
+					}
+					continue
+				}
 				f2(ctx, v)
-			case v := <-ch3:
+			case v, ok := <-ch3:
+				if !ok {
+					ch3 = nil
+					if t.closed(ctx, 2, &open) {
+						return nil
+					}
+					continue
+				}
 				f3(ctx, v)
-			case v := <-ch4:
+			case v, ok := <-ch4:
+				if !ok {
+					ch4 = nil
+					if t.closed(ctx, 3, &open) {
+						return nil
+					}
+					continue
+				}
 				f4(ctx, v)
-			case v := <-ch5:
+			case v, ok := <-ch5:
+				if !ok {
+					ch5 = nil
+					if t.closed(ctx, 4, &open) {
+						return nil
+					}
+					continue
+				}
 				f5(ctx, v)
-			case v := <-ch6:
+			case v, ok := <-ch6:
+				if !ok {
+					ch6 = nil
+					if t.closed(ctx, 5, &open) {
+						return nil
+					}
+					continue
+				}
 				f6(ctx, v)
 			case <-ctx.Done():
-				if o.cleanup {
-					cleanup(ctx, ch1, f1)
-					cleanup(ctx, ch2, f2)
-					cleanup(ctx, ch3, f3)
-					cleanup(ctx, ch4, f4)
-					cleanup(ctx, ch5, f5)
-					cleanup(ctx, ch6, f6)
+				if t.cleanup {
+					cleanup(t, ctx, ch1, f1)
+					cleanup(t, ctx, ch2, f2)
+					cleanup(t, ctx, ch3, f3)
+					cleanup(t, ctx, ch4, f4)
+					cleanup(t, ctx, ch5, f5)
+					cleanup(t, ctx, ch6, f6)
 				}
-				return
+				return nil
 			}
 		}
-	}()
-	return h
+	})
 }
@@ -2,6 +2,7 @@ package spawn_test
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -96,3 +97,518 @@ func TestTick_Cancel(t *testing.T) {
 		t.Errorf("Expected function to be called at least 2 times, got %d", count)
 	}
 }
+
+func TestRunErr(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+
+	handle := spawn.RunErr(ctx, func(ctx context.Context) error {
+		return errBad
+	})
+	handle.Join(ctx)
+	if !errors.Is(handle.Err(), errBad) {
+		t.Errorf("Expected error %v, got %v", errBad, handle.Err())
+	}
+
+	handle = spawn.Run(ctx, func(ctx context.Context) {})
+	handle.Join(ctx)
+	if err := handle.Err(); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+func TestWithRecover(t *testing.T) {
+	ctx := context.Background()
+	var recovered any
+	var stack []byte
+
+	handle := spawn.Run(ctx, func(ctx context.Context) {
+		panic("boom")
+	}, spawn.WithRecover(func(v any, s []byte) {
+		recovered, stack = v, s
+	}))
+	handle.Join(ctx)
+
+	if !handle.Panicked() {
+		t.Errorf("Expected task to have panicked")
+	}
+	if recovered != "boom" {
+		t.Errorf("Expected recovered value %q, got %v", "boom", recovered)
+	}
+	if len(stack) == 0 {
+		t.Errorf("Expected non-empty stack trace")
+	}
+	var pe *spawn.PanicError
+	if !errors.As(handle.Err(), &pe) || pe.Value != "boom" {
+		t.Errorf("Expected PanicError with value %q, got %v", "boom", handle.Err())
+	}
+
+	handle = spawn.Run(ctx, func(ctx context.Context) {}, spawn.WithRecover(func(any, []byte) {}))
+	handle.Join(ctx)
+	if handle.Panicked() {
+		t.Errorf("Expected task not to have panicked")
+	}
+}
+
+func TestAfter(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	start := time.Now()
+	handle := spawn.After(ctx, 50*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	})
+	handle.Join(ctx)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected function to be delayed by at least 50ms, got %v", elapsed)
+	}
+	if atomic.LoadInt32(&called) != 1 {
+		t.Errorf("Expected function to be called once")
+	}
+
+	handle = spawn.After(ctx, time.Hour, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	})
+	handle.Cancel()
+	handle.Join(ctx)
+	if atomic.LoadInt32(&called) != 1 {
+		t.Errorf("Expected canceled function not to be called")
+	}
+}
+
+func TestTickImmediate(t *testing.T) {
+	ctx := context.Background()
+	called := make(chan struct{}, 1)
+
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+	}, time.Hour, spawn.WithImmediate())
+	defer handle.Cancel()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Errorf("Expected function to be called immediately")
+	}
+}
+
+func TestTickJitter(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	}, 20*time.Millisecond, spawn.WithJitter(0.5))
+
+	time.Sleep(200 * time.Millisecond)
+	handle.Cancel()
+	handle.Join(ctx)
+
+	// Each interval is between 10ms and 30ms.
+	count := atomic.LoadInt32(&called)
+	if count < 3 || count > 20 {
+		t.Errorf("Expected function to be called between 3 and 20 times, got %d", count)
+	}
+}
+
+func TestTickFunc(t *testing.T) {
+	ctx := context.Background()
+	var delays []time.Duration
+
+	start := time.Now()
+	handle := spawn.TickFunc(ctx, func(ctx context.Context) time.Duration {
+		delays = append(delays, time.Since(start))
+		if len(delays) == 3 {
+			return 0
+		}
+		return time.Duration(len(delays)) * 20 * time.Millisecond
+	})
+	handle.Join(ctx)
+
+	if len(delays) != 3 {
+		t.Fatalf("Expected function to be called 3 times, got %d", len(delays))
+	}
+	if delays[1] < 20*time.Millisecond || delays[2] < 60*time.Millisecond {
+		t.Errorf("Expected calls after returned delays, got %v", delays)
+	}
+}
+
+func TestDone(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+
+	handle := spawn.Run(ctx, func(ctx context.Context) {
+		<-release
+	})
+	select {
+	case <-handle.Done():
+		t.Errorf("Expected task not to be completed")
+	default:
+	}
+	close(release)
+	select {
+	case <-handle.Done():
+	case <-time.After(time.Second):
+		t.Errorf("Expected task to be completed")
+	}
+}
+
+func TestChanPriority(t *testing.T) {
+	ctx := context.Background()
+	control := make(chan int, 10)
+	data := make(chan int, 10)
+	for i := range 10 {
+		control <- i
+		data <- i
+	}
+
+	var order []string
+	done := make(chan struct{})
+	handle := spawn.Chan2(ctx, control, func(ctx context.Context, v int) {
+		order = append(order, "control")
+	}, data, func(ctx context.Context, v int) {
+		order = append(order, "data")
+		if v == 9 {
+			close(done)
+		}
+	}, spawn.WithPriority())
+	<-done
+	handle.Cancel()
+	handle.Join(ctx)
+
+	for i, s := range order {
+		if want := map[bool]string{true: "control", false: "data"}[i < 10]; s != want {
+			t.Fatalf("Expected all control values before data values, got %v", order)
+		}
+	}
+}
+
+func TestChanDrainLimit(t *testing.T) {
+	ctx := context.Background()
+	ch := make(chan int, 10)
+	for i := range 10 {
+		ch <- i
+	}
+
+	var handled int64
+	started := make(chan struct{})
+	handle := spawn.Chan(ctx, ch, func(ctx context.Context, v int) {
+		if handled == 0 {
+			close(started)
+			<-ctx.Done()
+		}
+		handled++
+	}, spawn.WithCleanup(true), spawn.WithDrainLimit(3))
+	<-started
+	handle.Cancel()
+	handle.Join(ctx)
+
+	stats := handle.Stats()
+	if stats.Drained > 3 {
+		t.Errorf("Expected at most 3 values drained, got %d", stats.Drained)
+	}
+	if stats.Dropped != 10-handled || len(ch) != 0 {
+		t.Errorf("Expected %d values dropped, got %d", 10-handled, stats.Dropped)
+	}
+}
+
+func TestChanClosed(t *testing.T) {
+	ctx := context.Background()
+	ch1 := make(chan int)
+	ch2 := make(chan int)
+	var closed []int
+	var values int32
+
+	handle := spawn.Chan2(ctx, ch1, func(ctx context.Context, v int) {
+		atomic.AddInt32(&values, 1)
+	}, ch2, func(ctx context.Context, v int) {
+		atomic.AddInt32(&values, 1)
+	}, spawn.WithOnClosed(func(ctx context.Context, index int) {
+		closed = append(closed, index)
+	}))
+	close(ch1)
+	ch2 <- 1
+	close(ch2)
+	handle.Join(ctx)
+
+	if len(closed) != 2 || closed[0] != 0 || closed[1] != 1 {
+		t.Errorf("Expected channels 0 and 1 to be closed in order, got %v", closed)
+	}
+	if n := atomic.LoadInt32(&values); n != 1 {
+		t.Errorf("Expected 1 value to be processed, got %d", n)
+	}
+
+	ch3 := make(chan int)
+	ch4 := make(chan int)
+	handle = spawn.Chan2(ctx, ch3, func(ctx context.Context, v int) {}, ch4, func(ctx context.Context, v int) {}, spawn.WithExitOnClosed())
+	close(ch3)
+	select {
+	case <-handle.Done():
+	case <-time.After(time.Second):
+		t.Errorf("Expected task to exit when a channel is closed")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	handle := spawn.RunErr(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		if !errors.Is(context.Cause(ctx), spawn.ErrTimeout) {
+			t.Errorf("Expected cause %v, got %v", spawn.ErrTimeout, context.Cause(ctx))
+		}
+		return ctx.Err()
+	}, spawn.WithTimeout(10*time.Millisecond))
+	handle.Join(ctx)
+	if !handle.TimedOut() {
+		t.Errorf("Expected task to have timed out")
+	}
+	if !errors.Is(handle.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", handle.Err())
+	}
+
+	handle = spawn.Run(ctx, func(ctx context.Context) {}, spawn.WithTimeout(time.Hour))
+	handle.Join(ctx)
+	if handle.TimedOut() {
+		t.Errorf("Expected task not to have timed out")
+	}
+}
+
+func TestEvery(t *testing.T) {
+	ctx := context.Background()
+	period := 100 * time.Millisecond
+	offset := 10 * time.Millisecond
+	times := make(chan time.Time, 10)
+
+	handle := spawn.Every(ctx, period, offset, func(ctx context.Context) {
+		times <- time.Now()
+	})
+	defer handle.Cancel()
+
+	for range 3 {
+		select {
+		case now := <-times:
+			if d := now.Sub(now.Truncate(period)); d < offset || d > offset+50*time.Millisecond {
+				t.Errorf("Expected call at %v past a period boundary, got %v", offset, d)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected function to be called")
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+
+	running := spawn.Run(ctx, func(ctx context.Context) {
+		<-release
+	})
+	for running.State() == spawn.TaskPending {
+		time.Sleep(time.Millisecond)
+	}
+	if !running.Running() {
+		t.Errorf("Expected task to be running, got %v", running.State())
+	}
+	close(release)
+	running.Join(ctx)
+	if s := running.State(); s != spawn.TaskCompleted {
+		t.Errorf("Expected completed, got %v", s)
+	}
+
+	stopping := make(chan struct{})
+	canceled := spawn.Run(ctx, func(ctx context.Context) {
+		<-ctx.Done()
+		<-stopping
+	})
+	canceled.Cancel()
+	for canceled.State() == spawn.TaskPending {
+		time.Sleep(time.Millisecond)
+	}
+	if s := canceled.State(); s != spawn.TaskCanceling || !canceled.Running() {
+		t.Errorf("Expected canceling, got %v", s)
+	}
+	close(stopping)
+	canceled.Join(ctx)
+	if s := canceled.State(); s != spawn.TaskCanceled {
+		t.Errorf("Expected canceled, got %v", s)
+	}
+
+	panicked := spawn.Run(ctx, func(ctx context.Context) {
+		panic("boom")
+	}, spawn.WithRecover(func(any, []byte) {}))
+	panicked.Join(ctx)
+	if s := panicked.State(); s != spawn.TaskPanicked {
+		t.Errorf("Expected panicked, got %v", s)
+	}
+}
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	errBad := errors.New("bad")
+
+	handle := spawn.RunErr(ctx, func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return errBad
+	})
+	handle.Join(ctx)
+	stats := handle.Stats()
+	if stats.Runs != 1 || stats.LastError != errBad || stats.LastDuration < 10*time.Millisecond {
+		t.Errorf("Expected 1 failed run of at least 10ms, got %+v", stats)
+	}
+	if stats.Started.IsZero() || stats.Completed.Before(stats.Started) {
+		t.Errorf("Expected start and completion times, got %+v", stats)
+	}
+
+	var called int32
+	handle = spawn.Tick(ctx, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	}, 10*time.Millisecond)
+	time.Sleep(55 * time.Millisecond)
+	if stats := handle.Stats(); !stats.Completed.IsZero() {
+		t.Errorf("Expected running task not to be completed, got %+v", stats)
+	}
+	handle.Cancel()
+	handle.Join(ctx)
+	if stats := handle.Stats(); stats.Runs != int64(atomic.LoadInt32(&called)) || stats.Runs == 0 {
+		t.Errorf("Expected %d runs, got %+v", called, stats)
+	}
+}
+
+func TestTickMaxRuns(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	}, time.Millisecond, spawn.MaxRuns(3))
+	handle.Join(ctx)
+	if n := atomic.LoadInt32(&called); n != 3 {
+		t.Errorf("Expected function to be called 3 times, got %d", n)
+	}
+	if s := handle.State(); s != spawn.TaskCompleted {
+		t.Errorf("Expected completed, got %v", s)
+	}
+}
+
+func TestTickUntil(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	}, time.Millisecond, spawn.Until(func(ctx context.Context) bool {
+		return atomic.LoadInt32(&called) == 5
+	}))
+	handle.Join(ctx)
+	if n := atomic.LoadInt32(&called); n != 5 {
+		t.Errorf("Expected function to be called 5 times, got %d", n)
+	}
+	if s := handle.State(); s != spawn.TaskCompleted {
+		t.Errorf("Expected completed, got %v", s)
+	}
+}
+
+func TestChanSaturation(t *testing.T) {
+	ctx := context.Background()
+	metrics := spawn.NewMetrics()
+	spawn.SetMetricsRecorder(metrics)
+	defer spawn.SetMetricsRecorder(nil)
+
+	ch := make(chan int, 4)
+	block := make(chan struct{})
+	samples := make(chan [2]int, 100)
+	handle := spawn.Chan(ctx, ch, func(ctx context.Context, v int) {
+		<-block
+	}, spawn.Named("test-saturation"), spawn.WithSaturation(5*time.Millisecond, func(ctx context.Context, index, length, capacity int) {
+		select {
+		case samples <- [2]int{length, capacity}:
+		default:
+		}
+	}))
+	for i := range 5 {
+		ch <- i
+	}
+
+	deadline := time.After(time.Second)
+	for full := false; !full; {
+		select {
+		case s := <-samples:
+			full = s == [2]int{4, 4}
+		case <-deadline:
+			t.Fatalf("Expected a sample of a full channel")
+		}
+	}
+	if m := metrics.Snapshot()["test-saturation"]; len(m.Channels) != 1 || m.Channels[0].Cap != 4 {
+		t.Errorf("Expected saturation of 1 channel in metrics, got %+v", m.Channels)
+	}
+	close(block)
+	handle.Cancel()
+	handle.Join(ctx)
+}
+
+func TestRestartOnPanic(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	handle := spawn.Tick(ctx, func(ctx context.Context) {
+		if atomic.AddInt32(&called, 1) == 1 {
+			panic("boom")
+		}
+	}, time.Millisecond, spawn.WithRestartOnPanic(time.Millisecond), spawn.MaxRuns(3))
+	handle.Join(ctx)
+	if n := atomic.LoadInt32(&called); n != 3 {
+		t.Errorf("Expected function to be called 3 times, got %d", n)
+	}
+	if handle.Panicked() {
+		t.Errorf("Expected task not to complete because of the panic")
+	}
+
+	ch := make(chan int)
+	var sum int32
+	handle = spawn.Chan(ctx, ch, func(ctx context.Context, v int) {
+		if v == 0 {
+			panic("zero")
+		}
+		atomic.AddInt32(&sum, int32(v))
+	}, spawn.WithRestartOnPanic(time.Millisecond))
+	for _, v := range []int{1, 0, 2} {
+		ch <- v
+	}
+	close(ch)
+	handle.Join(ctx)
+	if n := atomic.LoadInt32(&sum); n != 3 {
+		t.Errorf("Expected sum 3 after the panic, got %d", n)
+	}
+}
+
+func TestUnsupportedOption(t *testing.T) {
+	ctx := context.Background()
+	expectPanic := func(want string, f func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r != want {
+				t.Errorf("Expected panic %q, got %v", want, r)
+			}
+		}()
+		f()
+	}
+	expectPanic("unsupported option MaxRuns for Run", func() {
+		spawn.Run(ctx, func(ctx context.Context) {}, spawn.MaxRuns(1))
+	})
+	expectPanic("unsupported option WithMaxBatch for Chan", func() {
+		spawn.Chan(ctx, make(chan int), func(ctx context.Context, v int) {}, spawn.WithCleanup(true), spawn.WithMaxBatch(1))
+	})
+	expectPanic("unsupported option WithImmediate for Every", func() {
+		spawn.Every(ctx, time.Hour, 0, func(ctx context.Context) {}, spawn.WithImmediate())
+	})
+	expectPanic("unsupported option WithMaxBatch for Buffer", func() {
+		spawn.Buffer(ctx, time.Second, 10, func(ctx context.Context, v []int) {}, spawn.WithMaxBatch(1))
+	})
+	expectPanic("unsupported option WithTimeout for Map", func() {
+		spawn.Map(ctx, []int{1}, 1, func(ctx context.Context, v int) (int, error) { return v, nil }, spawn.WithTimeout(time.Second))
+	})
+}
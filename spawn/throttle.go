@@ -0,0 +1,57 @@
+package spawn
+
+import (
+	"context"
+	"time"
+)
+
+// WithTrailing makes Throttle run f once more at the end of an interval if trigger was called
+// during that interval. By default, such calls are dropped.
+func WithTrailing() Option {
+	return option(kindTrailing, "WithTrailing", func(o *taskOptions) {
+		o.trailing = true
+	})
+}
+
+// Throttle starts a new concurrent task that runs f at most once per interval d, no matter how
+// often the returned trigger function is called. A call to trigger runs f immediately if no run
+// has started within the last interval; otherwise it is dropped, or deferred to the end of the
+// interval with WithTrailing. The trigger function never blocks and is safe for concurrent use.
+func Throttle(ctx context.Context, d time.Duration, f func(context.Context), options ...Option) (trigger func(), h Handle) {
+	if d <= 0 {
+		panic("non-positive interval for Throttle")
+	}
+	t := newTask("Throttle", kindTask|kindPeriodic|kindRestart|kindTrailing, options)
+	f = t.counted(f)
+	signal := make(chan struct{}, 1)
+	trigger = func() {
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+	h = start(ctx, t, func(ctx context.Context) error {
+		for {
+			select {
+			case <-signal:
+			case <-ctx.Done():
+				return nil
+			}
+			timer := time.NewTimer(d)
+			f(ctx)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			}
+			if !t.trailing {
+				select {
+				case <-signal:
+				default:
+				}
+			}
+		}
+	})
+	return trigger, h
+}
@@ -0,0 +1,50 @@
+package spawn_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestThrottle(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	trigger, handle := spawn.Throttle(ctx, 200*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	})
+	for range 10 {
+		trigger()
+		time.Sleep(2 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+	handle.Cancel()
+	handle.Join(ctx)
+
+	if n := atomic.LoadInt32(&called); n != 1 {
+		t.Errorf("Expected function to be called once, got %d", n)
+	}
+}
+
+func TestThrottleTrailing(t *testing.T) {
+	ctx := context.Background()
+	var called int32
+
+	trigger, handle := spawn.Throttle(ctx, 200*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&called, 1)
+	}, spawn.WithTrailing())
+	for range 10 {
+		trigger()
+		time.Sleep(2 * time.Millisecond)
+	}
+	time.Sleep(300 * time.Millisecond)
+	handle.Cancel()
+	handle.Join(ctx)
+
+	if n := atomic.LoadInt32(&called); n != 2 {
+		t.Errorf("Expected function to be called twice, got %d", n)
+	}
+}
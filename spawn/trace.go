@@ -0,0 +1,82 @@
+package spawn
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Tracer traces the execution of tasks started with WithTracer. It can be implemented on top of
+// OpenTelemetry, for example, by starting a span in Start and ending it in the returned function:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, func(spawn.TaskState, error)) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, func(state spawn.TaskState, err error) {
+//			span.SetAttributes(attribute.String("task.state", state.String()))
+//			if err != nil {
+//				span.RecordError(err)
+//				span.SetStatus(codes.Error, err.Error())
+//			}
+//			span.End()
+//		}
+//	}
+type Tracer interface {
+	// Start is called when a task starts, with the context of the task and its name given with Named,
+	// or the empty string. The returned context, which must be derived from ctx and may carry a span,
+	// is passed to the task.
+	// The returned function is called when the task completes, with its final state and error.
+	Start(ctx context.Context, name string) (context.Context, func(TaskState, error))
+}
+
+// untraced is the end function of tasks without a Tracer.
+func untraced(TaskState, error) {}
+
+// TracerFunc is an adapter to use a function as a Tracer.
+type TracerFunc func(ctx context.Context, name string) (context.Context, func(TaskState, error))
+
+// Start calls f(ctx, name).
+func (f TracerFunc) Start(ctx context.Context, name string) (context.Context, func(TaskState, error)) {
+	return f(ctx, name)
+}
+
+// WithTracer traces the task with t. Since the task context is derived from the context passed to
+// the function that starts the task, the trace context of the request that started a background
+// task is propagated to it, so the task is correlated with the request.
+func WithTracer(t Tracer) Option {
+	if t == nil {
+		panic("nil tracer for WithTracer")
+	}
+	return option(kindTask, "WithTracer", func(o *taskOptions) {
+		o.tracer = t
+	})
+}
+
+// SlogTracer returns a Tracer that logs the completion of each task to logger, or slog.Default()
+// if logger is nil, with a "task" group holding the name, duration, and state of the task.
+// Tasks that fail are logged at the error level, other tasks at the debug level.
+func SlogTracer(logger *slog.Logger) Tracer {
+	return TracerFunc(func(ctx context.Context, name string) (context.Context, func(TaskState, error)) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		started := time.Now()
+		return ctx, func(state TaskState, err error) {
+			attrs := []any{
+				slog.String("name", name),
+				slog.Duration("duration", time.Since(started)),
+				slog.String("state", state.String()),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			level := slog.LevelDebug
+			if err != nil && state != TaskCanceled {
+				level = slog.LevelError
+			}
+			l.Log(ctx, level, "spawn: task completed", slog.Group("task", attrs...))
+		}
+	})
+}
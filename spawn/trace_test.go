@@ -0,0 +1,58 @@
+package spawn_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+type traceKey struct{}
+
+func TestWithTracer(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	var traced string
+	var state spawn.TaskState
+	var traceErr error
+
+	tracer := spawn.TracerFunc(func(ctx context.Context, name string) (context.Context, func(spawn.TaskState, error)) {
+		return context.WithValue(ctx, traceKey{}, "span:"+name), func(s spawn.TaskState, err error) {
+			state, traceErr = s, err
+		}
+	})
+	handle := spawn.RunErr(ctx, func(ctx context.Context) error {
+		traced, _ = ctx.Value(traceKey{}).(string)
+		return errFailed
+	}, spawn.Named("job"), spawn.WithTracer(tracer))
+	handle.Join(ctx)
+
+	if traced != "span:job" {
+		t.Errorf("Expected trace context to be propagated, got %q", traced)
+	}
+	if state != spawn.TaskCompleted || traceErr != errFailed {
+		t.Errorf("Expected (completed, %v), got (%v, %v)", errFailed, state, traceErr)
+	}
+}
+
+func TestSlogTracer(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handle := spawn.RunErr(ctx, func(ctx context.Context) error {
+		return errors.New("failed")
+	}, spawn.Named("job"), spawn.WithTracer(spawn.SlogTracer(logger)))
+	handle.Join(ctx)
+
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "task.name=job", "task.state=completed", "task.error=failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected log to contain %q, got %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package spawn
+
+import (
+	"context"
+	"reflect"
+)
+
+// WaitAll waits for all of the tasks to complete. It returns the context error if the context
+// is canceled first, or nil.
+func WaitAll(ctx context.Context, handles ...Handle) error {
+	for _, h := range handles {
+		select {
+		case <-h.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// WaitAny waits for any of the tasks to complete and returns its index in handles.
+// It returns -1 if the context is canceled first or no handles are given.
+func WaitAny(ctx context.Context, handles ...Handle) int {
+	if len(handles) == 0 {
+		return -1
+	}
+	cases := make([]reflect.SelectCase, len(handles)+1)
+	for i, h := range handles {
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(h.Done())}
+	}
+	cases[len(handles)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	i, _, _ := reflect.Select(cases)
+	if i == len(handles) {
+		return -1
+	}
+	return i
+}
@@ -0,0 +1,46 @@
+package spawn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gopherd/exp/spawn"
+)
+
+func TestWaitAll(t *testing.T) {
+	ctx := context.Background()
+	h1 := spawn.After(ctx, 10*time.Millisecond, func(ctx context.Context) {})
+	h2 := spawn.After(ctx, 20*time.Millisecond, func(ctx context.Context) {})
+	if err := spawn.WaitAll(ctx, h1, h2); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	h3 := spawn.After(ctx, time.Hour, func(ctx context.Context) {})
+	defer h3.Cancel()
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := spawn.WaitAll(timeout, h1, h3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestWaitAny(t *testing.T) {
+	ctx := context.Background()
+	h1 := spawn.After(ctx, time.Hour, func(ctx context.Context) {})
+	defer h1.Cancel()
+	h2 := spawn.After(ctx, 10*time.Millisecond, func(ctx context.Context) {})
+	if i := spawn.WaitAny(ctx, h1, h2); i != 1 {
+		t.Errorf("Expected index 1, got %d", i)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if i := spawn.WaitAny(timeout, h1); i != -1 {
+		t.Errorf("Expected -1 on timeout, got %d", i)
+	}
+	if i := spawn.WaitAny(ctx); i != -1 {
+		t.Errorf("Expected -1 for no handles, got %d", i)
+	}
+}
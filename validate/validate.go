@@ -14,3 +14,26 @@ var (
 func OneOf[S ~[]T, T comparable](x T, s S) error {
 	return op.If(slices.Contains(s, x), nil, ErrNotOneOf)
 }
+
+// Rule is a function that validates a value of type T and returns an error if it is invalid.
+//
+// Rules can be used with chain.Validated to validate values flowing through a chain.
+type Rule[T any] func(T) error
+
+// In returns a Rule that checks whether the value is one of the allowed values.
+func In[S ~[]T, T comparable](s S) Rule[T] {
+	return func(x T) error {
+		return OneOf(x, s)
+	}
+}
+
+// All validates x against all rules and returns the joined errors of the failed rules, or nil.
+func All[T any](x T, rules ...Rule[T]) error {
+	var errs []error
+	for _, rule := range rules {
+		if err := rule(x); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}